@@ -0,0 +1,34 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// adjtimePath is the hwclock(8) drift state file.
+const adjtimePath = "/etc/adjtime"
+
+// parseAdjtimeDrift returns the drift factor from the contents of
+// /etc/adjtime, the first field of its first line.
+func parseAdjtimeDrift(s string) (drift float64, err error) {
+	fields := strings.Fields(strings.SplitN(s, "\n", 2)[0])
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("malformed adjtime %q", s)
+	}
+	if drift, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return 0, fmt.Errorf("malformed adjtime %q: %w", s, err)
+	}
+	return drift, nil
+}
+
+// parseAdjtimeLocal reports whether the contents of /etc/adjtime declare that
+// the RTC keeps local time, with LOCAL on its third line. hwclock assumes UTC
+// when the line is missing.
+func parseAdjtimeLocal(s string) (local bool) {
+	lines := strings.Split(s, "\n")
+	return len(lines) >= 3 && strings.TrimSpace(lines[2]) == "LOCAL"
+}
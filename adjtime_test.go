@@ -0,0 +1,25 @@
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAdjtimeDrift(t *testing.T) {
+	drift, err := parseAdjtimeDrift("-1.234567 1593120114 0.000000\n1593120114\nUTC\n")
+	require.NoError(t, err)
+	assert.Equal(t, -1.234567, drift)
+
+	_, err = parseAdjtimeDrift("")
+	assert.Error(t, err)
+	_, err = parseAdjtimeDrift("UTC\n")
+	assert.Error(t, err)
+}
+
+func TestParseAdjtimeLocal(t *testing.T) {
+	assert.True(t, parseAdjtimeLocal("0.0 0 0.0\n0\nLOCAL\n"))
+	assert.False(t, parseAdjtimeLocal("0.0 0 0.0\n0\nUTC\n"))
+	assert.False(t, parseAdjtimeLocal("0.0 0 0.0\n"))
+}
@@ -0,0 +1,68 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// AlarmKind identifies one of the two alarm facilities of a real-time clock.
+//
+// The standard alarm is programmed with RTC_ALM_SET. It only encodes a time of
+// day, so it fires within the next 24 hours, and only while the alarm
+// interrupt is enabled with SetAlarmInterrupt. It is accessed with GetAlarm
+// and SetAlarm.
+//
+// The wake alarm is programmed with RTC_WKALM_SET. It carries a full date and
+// its own enabled flag, and can wake the system from suspend. It is accessed
+// with WakeAlarm, SetWakeAlarm and CancelWakeAlarm.
+//
+// On most devices both facilities are backed by the same hardware alarm, so
+// programming one replaces the other.
+type AlarmKind int
+
+const (
+	AlarmStandard AlarmKind = iota
+	AlarmWake
+)
+
+func (k AlarmKind) String() string {
+	switch k {
+	case AlarmStandard:
+		return "standard"
+	case AlarmWake:
+		return "wake"
+	default:
+		return fmt.Sprintf("AlarmKind(%d)", int(k))
+	}
+}
+
+// WakeAlarm is the state of a real-time clock's wake alarm.
+type WakeAlarm struct {
+	// Enabled is set when the alarm is armed.
+	Enabled bool
+	// Pending is set when the alarm has fired but has not been handled.
+	Pending bool
+	// Time is the time at which the alarm fires.
+	Time time.Time
+}
+
+// WakeAlarm returns the state of the real-time clock's wake alarm, read with
+// RTC_WKALM_RD.
+func (c *RTC) WakeAlarm() (a WakeAlarm, err error) {
+	wk := new(unix.RTCWkAlrm)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_WKALM_RD, uintptr(unsafe.Pointer(wk))); errno != 0 {
+		return WakeAlarm{}, fmt.Errorf("failed to read real-time clock wake alarm: %w", wrapErrno(errno))
+	}
+	return WakeAlarm{
+		Enabled: wk.Enabled == 1,
+		Pending: wk.Pending == 1,
+		Time:    c.fromRTC(wk.Time),
+	}, nil
+}
@@ -0,0 +1,197 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// BenchmarkOptions configures Benchmark.
+type BenchmarkOptions struct {
+	// Samples is the number of ioctl calls timed for each latency measurement.
+	// Defaults to 100.
+	Samples int
+	// Frequencies are the periodic interrupt frequencies at which jitter is
+	// measured. Defaults to 2, 64 and 1024 Hz.
+	Frequencies []uint
+	// JitterDuration is how long interrupts are observed at each frequency.
+	// Defaults to two seconds.
+	JitterDuration time.Duration
+	// SetTime enables measuring RTC_SET_TIME latency and accuracy, which sets
+	// the RTC to the system time.
+	SetTime bool
+}
+
+// LatencyStats summarizes the latency of a repeated operation.
+type LatencyStats struct {
+	Samples int           `json:"samples"`
+	Min     time.Duration `json:"min"`
+	Median  time.Duration `json:"median"`
+	Max     time.Duration `json:"max"`
+}
+
+// JitterStats summarizes the deviation of periodic interrupt arrivals from
+// their nominal interval.
+type JitterStats struct {
+	Frequency  uint          `json:"frequency"`
+	Interrupts int           `json:"interrupts"`
+	Missed     uint64        `json:"missed"`
+	Mean       time.Duration `json:"mean"`
+	StdDev     time.Duration `json:"stddev"`
+	Max        time.Duration `json:"max"`
+}
+
+// BenchmarkReport is the result of Benchmark.
+type BenchmarkReport struct {
+	ReadTime  LatencyStats  `json:"read_time"`
+	ReadAlarm LatencyStats  `json:"read_alarm"`
+	SetTime   *LatencyStats `json:"set_time,omitempty"`
+	// SetTimeError is the offset of the RTC from the system clock measured
+	// right after setting it, if SetTime was enabled.
+	SetTimeError *time.Duration `json:"set_time_error,omitempty"`
+	Jitter       []JitterStats  `json:"jitter"`
+}
+
+// Benchmark measures the ioctl latencies of the specified real-time clock
+// device, optionally its set-time accuracy, and the delivery jitter of its
+// periodic interrupts at several frequencies. The report can be marshaled to
+// JSON to compare RTC hardware and kernel configurations.
+func Benchmark(dev string, opts BenchmarkOptions) (report BenchmarkReport, err error) {
+	if opts.Samples <= 0 {
+		opts.Samples = 100
+	}
+	if len(opts.Frequencies) == 0 {
+		opts.Frequencies = []uint{2, 64, 1024}
+	}
+	if opts.JitterDuration <= 0 {
+		opts.JitterDuration = 2 * time.Second
+	}
+
+	c, err := NewRTC(dev)
+	if err != nil {
+		return report, err
+	}
+	defer c.Close()
+
+	if report.ReadTime, err = measureLatency(opts.Samples, func() error {
+		_, err := c.GetTime()
+		return err
+	}); err != nil {
+		return report, err
+	}
+	if report.ReadAlarm, err = measureLatency(opts.Samples, func() error {
+		_, err := c.GetAlarm()
+		return err
+	}); err != nil {
+		return report, err
+	}
+
+	if opts.SetTime {
+		stats, err := measureLatency(opts.Samples, func() error {
+			return c.SetTime(time.Now())
+		})
+		if err != nil {
+			return report, err
+		}
+		report.SetTime = &stats
+
+		offset, err := c.MeasureOffset(2)
+		if err != nil {
+			return report, err
+		}
+		report.SetTimeError = &offset
+	}
+
+	origFreq, err := c.GetFrequency()
+	if err != nil {
+		return report, err
+	}
+	defer func() {
+		_ = c.SetFrequency(origFreq)
+	}()
+	for _, f := range opts.Frequencies {
+		stats, err := c.measureJitter(f, opts.JitterDuration)
+		if err != nil {
+			return report, err
+		}
+		report.Jitter = append(report.Jitter, stats)
+	}
+	return report, nil
+}
+
+func measureLatency(samples int, op func() error) (stats LatencyStats, err error) {
+	durations := make([]time.Duration, samples)
+	for i := range durations {
+		start := time.Now()
+		if err := op(); err != nil {
+			return stats, err
+		}
+		durations[i] = time.Since(start)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return LatencyStats{
+		Samples: samples,
+		Min:     durations[0],
+		Median:  durations[samples/2],
+		Max:     durations[samples-1],
+	}, nil
+}
+
+// measureJitter observes periodic interrupts at the given frequency and
+// summarizes how far their arrivals deviate from the nominal interval.
+func (c *RTC) measureJitter(frequency uint, duration time.Duration) (stats JitterStats, err error) {
+	if err := c.SetFrequency(frequency); err != nil {
+		return stats, err
+	}
+	if err := c.SetPeriodicInterrupt(true); err != nil {
+		return stats, err
+	}
+	defer func() {
+		_ = c.SetPeriodicInterrupt(false)
+	}()
+
+	stats.Frequency = frequency
+	interval := time.Second / time.Duration(frequency)
+	var deviations []float64
+	var prev time.Time
+	start := time.Now()
+	for time.Since(start) < duration {
+		e, err := c.ReadEvent()
+		if err != nil {
+			return stats, err
+		}
+		now := time.Now()
+		if !e.Periodic {
+			continue
+		}
+		stats.Interrupts++
+		if e.Count > 1 {
+			stats.Missed += uint64(e.Count - 1)
+		}
+		if !prev.IsZero() {
+			expected := interval * time.Duration(e.Count)
+			deviations = append(deviations, float64(now.Sub(prev)-expected))
+		}
+		prev = now
+	}
+
+	if len(deviations) == 0 {
+		return stats, nil
+	}
+	var sum, sumSq, max float64
+	for _, d := range deviations {
+		sum += math.Abs(d)
+		sumSq += d * d
+		if math.Abs(d) > max {
+			max = math.Abs(d)
+		}
+	}
+	n := float64(len(deviations))
+	stats.Mean = time.Duration(sum / n)
+	stats.StdDev = time.Duration(math.Sqrt(sumSq / n))
+	stats.Max = time.Duration(max)
+	return stats, nil
+}
@@ -0,0 +1,40 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureLatency(t *testing.T) {
+	calls := 0
+	stats, err := measureLatency(5, func() error {
+		calls++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, calls)
+	assert.Equal(t, 5, stats.Samples)
+	assert.LessOrEqual(t, stats.Min.Nanoseconds(), stats.Median.Nanoseconds())
+	assert.LessOrEqual(t, stats.Median.Nanoseconds(), stats.Max.Nanoseconds())
+
+	_, err = measureLatency(5, func() error {
+		return errors.New("failed")
+	})
+	assert.Error(t, err)
+}
+
+func TestBenchmark(t *testing.T) {
+	report, err := Benchmark("/dev/rtc", BenchmarkOptions{
+		Samples:        10,
+		Frequencies:    []uint{2, 64},
+		JitterDuration: time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10, report.ReadTime.Samples)
+	assert.Len(t, report.Jitter, 2)
+	assert.Nil(t, report.SetTime)
+}
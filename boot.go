@@ -0,0 +1,141 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// staUnsync is the STA_UNSYNC adjtimex(2) status bit, set while the system
+// clock is not synchronized to a reference.
+const staUnsync = 0x40
+
+// BootClockReport describes how far off the system clock was when the kernel
+// initialized it from the real-time clock at boot.
+type BootClockReport struct {
+	// Seeded reports whether the kernel initialized the system clock from
+	// this device at boot (hctosys).
+	Seeded bool
+	// SetTo is the time the kernel set the system clock to, taken from the
+	// kernel log. It is the zero time if the log entry is no longer available.
+	SetTo time.Time
+	// DriftFactor is the systematic drift of the RTC in seconds per day
+	// recorded by hwclock in /etc/adjtime, or 0 if unknown.
+	DriftFactor float64
+	// Uptime is the time elapsed since boot.
+	Uptime time.Duration
+	// Synchronized reports whether the system clock is currently
+	// synchronized by NTP. Only then does Offset reflect the true error of
+	// the RTC.
+	Synchronized bool
+	// Offset is the current offset of the RTC relative to the system clock.
+	// A positive offset means the RTC is ahead.
+	Offset time.Duration
+	// NTPCorrection is the first correction NTP applied to the system clock,
+	// as given to BootClockError, or nil if unknown. A positive correction
+	// moved the clock forward.
+	NTPCorrection *time.Duration
+	// Error is the estimated error of the system clock immediately after it
+	// was initialized from the RTC. A positive error means the system clock
+	// was ahead.
+	//
+	// If the first NTP correction is known, the error is taken to be its
+	// opposite, which leaves out only how far the system clock drifted
+	// between boot and that correction. Otherwise it is the current RTC
+	// offset corrected for the drift accumulated since boot. The kernel only
+	// reads whole seconds from the RTC and, depending on its version, sets
+	// the system clock to the start or the middle of that second, so that
+	// estimate is only accurate to within a second.
+	Error time.Duration
+}
+
+// BootClockError estimates how far off the system clock was when it was
+// initialized from the real-time clock at boot, so that fleets can quantify
+// cold-boot time error.
+// The estimate combines the first correction NTP made to the system clock,
+// if known, for example from the logs of the NTP client, and otherwise the
+// current RTC offset, which is only meaningful once NTP has corrected the
+// system clock, with the RTC drift recorded in /etc/adjtime. The kernel log
+// entry written when the clock was set is also reported. ntpCorrection is
+// nil if the first NTP correction is unknown.
+func (c *RTC) BootClockError(ntpCorrection *time.Duration) (r BootClockReport, err error) {
+	r.NTPCorrection = ntpCorrection
+	r.Seeded, _ = c.Hctosys()
+	if b, err := os.ReadFile(adjtimePath); err == nil {
+		r.DriftFactor, _ = parseAdjtimeDrift(string(b))
+	}
+	// Reading the kernel log may be restricted to privileged users.
+	r.SetTo, _ = kernelHctosysTime()
+
+	if r.Uptime, err = clockNow(unix.CLOCK_BOOTTIME); err != nil {
+		return r, err
+	}
+	tx := &unix.Timex{}
+	if _, err := unix.Adjtimex(tx); err != nil {
+		return r, fmt.Errorf("failed to read system clock status: %w", err)
+	}
+	r.Synchronized = tx.Status&staUnsync == 0
+
+	if r.Offset, err = c.MeasureOffset(3); err != nil {
+		return r, err
+	}
+
+	r.Error = estimateBootError(r)
+	return r, nil
+}
+
+// estimateBootError estimates the error of the system clock at boot from the
+// other fields of r.
+func estimateBootError(r BootClockReport) time.Duration {
+	if r.NTPCorrection != nil {
+		return -*r.NTPCorrection
+	}
+	drift := time.Duration(r.DriftFactor * float64(time.Second) * r.Uptime.Hours() / 24)
+	return r.Offset - drift
+}
+
+// hctosysRegexp matches the kernel log entry written when the system clock is
+// initialized from an RTC, e.g.
+// "rtc_cmos 00:00: setting system clock to 2020-06-25T21:21:54 UTC (1593120114)".
+var hctosysRegexp = regexp.MustCompile(`setting system clock to .*\((\d+)\)`)
+
+// kernelHctosysTime reads the kernel log and returns the time the system clock
+// was initialized to, or the zero time if the entry is not found.
+func kernelHctosysTime() (t time.Time, err error) {
+	n, err := unix.Klogctl(unix.SYSLOG_ACTION_SIZE_BUFFER, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read kernel log: %w", err)
+	}
+	buf := make([]byte, n)
+	if n, err = unix.Klogctl(unix.SYSLOG_ACTION_READ_ALL, buf); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read kernel log: %w", err)
+	}
+	return parseHctosysLog(buf[:n]), nil
+}
+
+// parseHctosysLog returns the time from the first hctosys entry in the kernel
+// log, or the zero time if there is none.
+func parseHctosysLog(log []byte) (t time.Time) {
+	s := bufio.NewScanner(bytes.NewReader(log))
+	for s.Scan() {
+		m := hctosysRegexp.FindSubmatch(s.Bytes())
+		if m == nil {
+			continue
+		}
+		sec, err := strconv.ParseInt(string(m[1]), 10, 64)
+		if err != nil {
+			continue
+		}
+		return time.Unix(sec, 0).UTC()
+	}
+	return time.Time{}
+}
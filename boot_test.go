@@ -0,0 +1,26 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHctosysLog(t *testing.T) {
+	log := "<6>[    0.512345] rtc_cmos 00:00: registered as rtc0\n" +
+		"<6>[    0.512400] rtc_cmos 00:00: setting system clock to 2020-06-25T21:21:54 UTC (1593120114)\n"
+	assert.True(t, parseHctosysLog([]byte(log)).Equal(time.Unix(1593120114, 0)))
+	assert.True(t, parseHctosysLog([]byte("<6>[    0.1] no clock here\n")).IsZero())
+}
+
+func TestEstimateBootError(t *testing.T) {
+	// An RTC 3s ahead that gains 1s a day, after two days
+	r := BootClockReport{Offset: 3 * time.Second, DriftFactor: 1, Uptime: 48 * time.Hour}
+	assert.Equal(t, time.Second, estimateBootError(r))
+
+	// The first NTP correction moved the clock 1.2s back
+	correction := -1200 * time.Millisecond
+	r.NTPCorrection = &correction
+	assert.Equal(t, 1200*time.Millisecond, estimateBootError(r))
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Clock is the interface implemented by real-time clock backends.
+// Applications can depend on Clock and select the backend at runtime, for
+// example with NewClock, or substitute fakes and alternative backends such as
+// simulated clocks in tests.
+type Clock interface {
+	// GetTime returns the clock's time.
+	GetTime() (time.Time, error)
+	// SetTime sets the clock's time.
+	SetTime(t time.Time) error
+	// GetAlarm returns the clock's alarm time.
+	GetAlarm() (time.Time, error)
+	// SetAlarm sets the clock's alarm time and returns the effective alarm
+	// time after rounding to the clock's resolution.
+	SetAlarm(t time.Time) (time.Time, error)
+	// SetAlarmInterrupt enables or disables the clock's alarm.
+	SetAlarmInterrupt(enable bool) error
+	// WaitAlarm blocks until the clock's alarm fires.
+	WaitAlarm() error
+	// ReadEvent blocks until an interrupt occurs and returns it.
+	ReadEvent() (Event, error)
+	// WaitForInterrupt waits up to timeout for an interrupt and returns it.
+	// If none occurs in time, the returned error wraps ErrTimeout.
+	WaitForInterrupt(timeout time.Duration) (Event, error)
+	// Close releases the clock's resources.
+	Close() error
+}
+
+var (
+	_ Clock = (*RTC)(nil)
+	_ Clock = (*SoftwareClock)(nil)
+)
+
+// NewClock opens the real-time clock device, or returns a SoftwareClock if the
+// device does not exist, so that machines without an RTC can run the same code.
+func NewClock(dev string) (Clock, error) {
+	c, err := NewRTC(dev)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewSoftwareClock()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
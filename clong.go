@@ -0,0 +1,7 @@
+//go:build !windows && !386 && !arm && !mips && !mipsle
+// +build !windows,!386,!arm,!mips,!mipsle
+
+package rtc
+
+// clong is the type of C long fields in the unix structs.
+type clong = int64
@@ -0,0 +1,8 @@
+//go:build !windows && (386 || arm || mips || mipsle)
+// +build !windows
+// +build 386 arm mips mipsle
+
+package rtc
+
+// clong is the type of C long fields in the unix structs.
+type clong = int32
@@ -0,0 +1,378 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeSource selects the time Configure sets the real-time clock to.
+type TimeSource int
+
+const (
+	// TimeSourceKeep leaves the RTC time untouched.
+	TimeSourceKeep TimeSource = iota
+	// TimeSourceSystem sets the RTC from the system clock.
+	TimeSourceSystem
+	// TimeSourceExplicit sets the RTC to Config.Time.
+	TimeSourceExplicit
+)
+
+func (s TimeSource) String() string {
+	switch s {
+	case TimeSourceKeep:
+		return "keep"
+	case TimeSourceSystem:
+		return "system"
+	case TimeSourceExplicit:
+		return "explicit"
+	default:
+		return fmt.Sprintf("TimeSource(%d)", int(s))
+	}
+}
+
+// Config is the desired state of a real-time clock applied by Configure.
+// Nil and zero fields leave the corresponding setting untouched.
+type Config struct {
+	// TimeSource selects the time the RTC is set to.
+	TimeSource TimeSource
+	// Time is the time the RTC is set to with TimeSourceExplicit.
+	Time time.Time
+	// TimeTolerance is the largest difference between the RTC and the
+	// selected time source that is left uncorrected. The RTC only has a
+	// resolution of one second, so differences below a second are always
+	// tolerated.
+	TimeTolerance time.Duration
+	// Frequency is the periodic interrupt frequency.
+	Frequency uint
+	// Alarm is the standard alarm time.
+	Alarm *time.Time
+	// AlarmInterrupt enables or disables the alarm interrupt.
+	AlarmInterrupt *bool
+	// WakeAlarm is the wake alarm time. The zero time cancels the wake alarm.
+	WakeAlarm *time.Time
+	// UpdateInterrupt enables or disables the update interrupt.
+	UpdateInterrupt *bool
+	// PeriodicInterrupt enables or disables the periodic interrupt.
+	PeriodicInterrupt *bool
+	// Offset is the frequency correction in parts per billion, set with
+	// SetFrequencyCorrection.
+	Offset *int64
+}
+
+// configState is the current state of the settings named in a Config.
+type configState struct {
+	// timeOffset is the offset of the RTC from the selected time source.
+	timeOffset     time.Duration
+	frequency      uint
+	alarm          time.Time
+	alarmInterrupt bool
+	wakeAlarm      WakeAlarm
+	offset         int64
+	// updateInterrupt and periodicInterrupt are nil if the device does not
+	// report their state.
+	updateInterrupt   *bool
+	periodicInterrupt *bool
+}
+
+// configDiff lists the settings of a Config that differ from the current state.
+type configDiff struct {
+	time           bool
+	frequency      bool
+	offset         bool
+	alarm          bool
+	wakeAlarm      bool
+	alarmInterrupt bool
+
+	updateInterrupt   bool
+	periodicInterrupt bool
+}
+
+// diffConfig compares the desired state with the current state. The update
+// and periodic interrupts are taken to differ if their state is unknown.
+func diffConfig(cfg Config, cur configState) (d configDiff) {
+	if cfg.TimeSource != TimeSourceKeep {
+		tolerance := cfg.TimeTolerance
+		if tolerance < time.Second {
+			tolerance = time.Second
+		}
+		d.time = cur.timeOffset >= tolerance || cur.timeOffset <= -tolerance
+	}
+	d.frequency = cfg.Frequency != 0 && cfg.Frequency != cur.frequency
+	d.offset = cfg.Offset != nil && *cfg.Offset != cur.offset
+	if cfg.Alarm != nil {
+		want := timeRtc{Time: *cfg.Alarm}.truncated()
+		d.alarm = want.Hour() != cur.alarm.Hour() || want.Minute() != cur.alarm.Minute() || want.Second() != cur.alarm.Second()
+	}
+	if cfg.WakeAlarm != nil {
+		if cfg.WakeAlarm.IsZero() {
+			d.wakeAlarm = cur.wakeAlarm.Enabled
+		} else {
+			d.wakeAlarm = !cur.wakeAlarm.Enabled || !timeRtc{Time: *cfg.WakeAlarm}.truncated().Equal(cur.wakeAlarm.Time)
+		}
+	}
+	d.alarmInterrupt = cfg.AlarmInterrupt != nil && *cfg.AlarmInterrupt != cur.alarmInterrupt
+	d.updateInterrupt = cfg.UpdateInterrupt != nil && (cur.updateInterrupt == nil || *cfg.UpdateInterrupt != *cur.updateInterrupt)
+	d.periodicInterrupt = cfg.PeriodicInterrupt != nil && (cur.periodicInterrupt == nil || *cfg.PeriodicInterrupt != *cur.periodicInterrupt)
+	return d
+}
+
+// configStep is one change applied by Configure along with its reversal.
+type configStep struct {
+	name  string
+	apply func() error
+	undo  func() error
+}
+
+// Configure applies the desired state in cfg to the real-time clock, giving
+// provisioning systems a single idempotent entry point.
+// The current state is read first and only the settings that differ are
+// changed. If a change fails, the changes already made are rolled back in
+// reverse order and the returned error describes both the failure and any
+// rollback failures. The kernel only reports the state of the update and
+// periodic interrupts for the RTC that set the system clock at boot; on other
+// devices they are always set, last, and are not rolled back.
+func (c *RTC) Configure(cfg Config) (err error) {
+	cur, err := c.configState(cfg)
+	if err != nil {
+		return err
+	}
+	d := diffConfig(cfg, cur)
+
+	var steps []configStep
+	if d.time {
+		steps = append(steps, c.configTimeStep(cfg, cur))
+	}
+	if d.frequency {
+		steps = append(steps, configStep{
+			name:  "frequency",
+			apply: func() error { return c.SetFrequency(cfg.Frequency) },
+			undo:  func() error { return c.SetFrequency(cur.frequency) },
+		})
+	}
+	if d.offset {
+		steps = append(steps, configStep{
+			name:  "offset",
+			apply: func() error { return c.SetFrequencyCorrection(*cfg.Offset) },
+			undo:  func() error { return c.SetFrequencyCorrection(cur.offset) },
+		})
+	}
+	if d.alarm {
+		steps = append(steps, configStep{
+			name: "alarm",
+			apply: func() error {
+				_, err := c.SetAlarm(*cfg.Alarm)
+				return err
+			},
+			// The alarm read back may be an armed wake alarm more than
+			// 24 hours ahead, so only its time of day is restored.
+			undo: func() error { return c.setAlarmTimeOfDay(cur.alarm) },
+		})
+	}
+	if d.wakeAlarm {
+		steps = append(steps, configStep{
+			name:  "wake alarm",
+			apply: func() error { return c.setWakeAlarmState(*cfg.WakeAlarm) },
+			undo: func() error {
+				if !cur.wakeAlarm.Enabled {
+					return c.CancelWakeAlarm()
+				}
+				return c.setWakeAlarmState(cur.wakeAlarm.Time)
+			},
+		})
+	}
+	// The alarm interrupt follows the alarms, since setting the wake alarm
+	// also enables it.
+	if d.alarmInterrupt || (d.wakeAlarm && cfg.AlarmInterrupt != nil) {
+		steps = append(steps, configStep{
+			name:  "alarm interrupt",
+			apply: func() error { return c.SetAlarmInterrupt(*cfg.AlarmInterrupt) },
+			undo:  func() error { return c.SetAlarmInterrupt(cur.alarmInterrupt) },
+		})
+	}
+	if d.updateInterrupt {
+		steps = append(steps, configStep{
+			name:  "update interrupt",
+			apply: func() error { return c.SetUpdateInterrupt(*cfg.UpdateInterrupt) },
+			undo:  interruptUndo(cur.updateInterrupt, c.SetUpdateInterrupt),
+		})
+	}
+	if d.periodicInterrupt {
+		steps = append(steps, configStep{
+			name:  "periodic interrupt",
+			apply: func() error { return c.SetPeriodicInterrupt(*cfg.PeriodicInterrupt) },
+			undo:  interruptUndo(cur.periodicInterrupt, c.SetPeriodicInterrupt),
+		})
+	}
+
+	return applyConfigSteps(steps)
+}
+
+// interruptUndo returns the undo function restoring an interrupt to the state
+// cur with set, or nil if the state is unknown.
+func interruptUndo(cur *bool, set func(enable bool) error) func() error {
+	if cur == nil {
+		return nil
+	}
+	enabled := *cur
+	return func() error { return set(enabled) }
+}
+
+// applyConfigSteps applies steps in order. If one fails, the steps already
+// applied are all undone in reverse order, and any rollback failures are
+// reported along with the failure in a *rollbackError.
+func applyConfigSteps(steps []configStep) (err error) {
+	for i, s := range steps {
+		if err := s.apply(); err != nil {
+			err = fmt.Errorf("failed to configure real-time clock %s: %w", s.name, err)
+			var rollback []error
+			for j := i - 1; j >= 0; j-- {
+				if steps[j].undo == nil {
+					continue
+				}
+				if uerr := steps[j].undo(); uerr != nil {
+					rollback = append(rollback, fmt.Errorf("rollback of %s failed: %w", steps[j].name, uerr))
+				}
+			}
+			if len(rollback) > 0 {
+				return &rollbackError{err: err, rollback: rollback}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackError is the failure of a configuration step along with the
+// failures to undo the steps applied before it. It unwraps to the step's
+// failure and also matches the rollback failures with errors.Is.
+type rollbackError struct {
+	err      error
+	rollback []error
+}
+
+func (e *rollbackError) Error() string {
+	msg := e.err.Error()
+	for _, err := range e.rollback {
+		msg += "; " + err.Error()
+	}
+	return msg
+}
+
+func (e *rollbackError) Unwrap() error {
+	return e.err
+}
+
+func (e *rollbackError) Is(target error) bool {
+	for _, err := range e.rollback {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// configState reads the current state of the settings named in cfg.
+func (c *RTC) configState(cfg Config) (cur configState, err error) {
+	if cfg.TimeSource != TimeSourceKeep {
+		t, err := c.GetTime()
+		if err != nil {
+			return cur, err
+		}
+		ref := time.Now()
+		if cfg.TimeSource == TimeSourceExplicit {
+			ref = cfg.Time
+		}
+		cur.timeOffset = t.Sub(ref.Truncate(time.Second))
+	}
+	if cfg.Frequency != 0 {
+		if cur.frequency, err = c.GetFrequency(); err != nil {
+			return cur, err
+		}
+	}
+	if cfg.Offset != nil {
+		if cur.offset, err = c.GetFrequencyCorrection(); err != nil {
+			return cur, err
+		}
+	}
+	if cfg.Alarm != nil {
+		if cur.alarm, err = c.GetAlarm(); err != nil {
+			return cur, err
+		}
+	}
+	if cfg.WakeAlarm != nil || cfg.AlarmInterrupt != nil {
+		// The enabled flag of the wake alarm reflects the alarm interrupt.
+		if cur.wakeAlarm, err = c.WakeAlarm(); err != nil {
+			return cur, err
+		}
+		cur.alarmInterrupt = cur.wakeAlarm.Enabled
+	}
+	if cfg.UpdateInterrupt != nil {
+		if cur.updateInterrupt, err = readInterruptState(c.GetUpdateInterrupt); err != nil {
+			return cur, err
+		}
+	}
+	if cfg.PeriodicInterrupt != nil {
+		if cur.periodicInterrupt, err = readInterruptState(c.GetPeriodicInterrupt); err != nil {
+			return cur, err
+		}
+	}
+	return cur, nil
+}
+
+// readInterruptState reads the state of an interrupt with get, returning nil
+// if the device does not report it.
+func readInterruptState(get func() (bool, error)) (enabled *bool, err error) {
+	on, err := get()
+	if errors.Is(err, ErrNotSupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &on, nil
+}
+
+// configTimeStep returns the step setting the RTC time. Its reversal restores
+// the previous time, advanced by the time elapsed since the change.
+func (c *RTC) configTimeStep(cfg Config, cur configState) (s configStep) {
+	var applied time.Time
+	return configStep{
+		name: "time",
+		apply: func() error {
+			applied = time.Now()
+			t := applied
+			if cfg.TimeSource == TimeSourceExplicit {
+				t = cfg.Time
+			}
+			return c.SetTime(t)
+		},
+		undo: func() error {
+			now := time.Now()
+			prev := now.Add(cur.timeOffset)
+			if cfg.TimeSource == TimeSourceExplicit {
+				prev = cfg.Time.Add(cur.timeOffset).Add(now.Sub(applied))
+			}
+			return c.SetTime(prev)
+		},
+	}
+}
+
+// setWakeAlarmState sets the wake alarm, or cancels it for the zero time.
+func (c *RTC) setWakeAlarmState(t time.Time) (err error) {
+	if t.IsZero() {
+		return c.CancelWakeAlarm()
+	}
+	_, err = c.SetWakeAlarm(t)
+	return err
+}
+
+// setAlarmTimeOfDay programs the standard alarm with SetDailyAlarm to fire at
+// the time of day of t, in the time zone the RTC keeps.
+func (c *RTC) setAlarmTimeOfDay(t time.Time) (err error) {
+	tm := c.toRTC(t)
+	return c.SetDailyAlarm(int(tm.Hour), int(tm.Min), int(tm.Sec))
+}
@@ -0,0 +1,93 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffConfig(t *testing.T) {
+	alarm := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	on := true
+	ppb := int64(-1200)
+	cur := configState{
+		timeOffset:     500 * time.Millisecond,
+		frequency:      64,
+		alarm:          alarm.AddDate(0, 0, -3),
+		alarmInterrupt: true,
+		wakeAlarm:      WakeAlarm{Enabled: true, Time: alarm},
+		offset:         ppb,
+	}
+
+	// Matching state is left untouched.
+	d := diffConfig(Config{
+		TimeSource:     TimeSourceSystem,
+		Frequency:      64,
+		Alarm:          &alarm,
+		AlarmInterrupt: &on,
+		WakeAlarm:      &alarm,
+		Offset:         &ppb,
+	}, cur)
+	assert.Equal(t, configDiff{}, d)
+
+	// Empty config changes nothing.
+	cur.timeOffset = time.Hour
+	assert.Equal(t, configDiff{}, diffConfig(Config{}, cur))
+
+	later := alarm.Add(time.Minute)
+	off := false
+	zero := time.Time{}
+	d = diffConfig(Config{
+		TimeSource:     TimeSourceSystem,
+		TimeTolerance:  2 * time.Hour,
+		Frequency:      2,
+		Alarm:          &later,
+		AlarmInterrupt: &off,
+		WakeAlarm:      &zero,
+	}, cur)
+	assert.Equal(t, configDiff{
+		frequency:      true,
+		alarm:          true,
+		wakeAlarm:      true,
+		alarmInterrupt: true,
+	}, d)
+
+	d = diffConfig(Config{TimeSource: TimeSourceExplicit}, cur)
+	assert.True(t, d.time)
+
+	// Interrupts are set if they differ or their state is unknown.
+	cur.updateInterrupt = &on
+	d = diffConfig(Config{UpdateInterrupt: &on, PeriodicInterrupt: &off}, cur)
+	assert.Equal(t, configDiff{periodicInterrupt: true}, d)
+	d = diffConfig(Config{UpdateInterrupt: &off}, cur)
+	assert.Equal(t, configDiff{updateInterrupt: true}, d)
+}
+
+func TestApplyConfigStepsRollback(t *testing.T) {
+	var undone []string
+	step := func(name string, applyErr, undoErr error) configStep {
+		return configStep{
+			name:  name,
+			apply: func() error { return applyErr },
+			undo: func() error {
+				undone = append(undone, name)
+				return undoErr
+			},
+		}
+	}
+	errApply := errors.New("apply failed")
+	errUndo := errors.New("undo failed")
+	err := applyConfigSteps([]configStep{
+		step("first", nil, nil),
+		step("second", nil, errUndo),
+		{name: "third", apply: func() error { return nil }},
+		step("fourth", errApply, nil),
+	})
+	// A failed undo does not stop the rollback of earlier steps.
+	assert.Equal(t, []string{"second", "first"}, undone)
+	assert.True(t, errors.Is(err, errApply))
+	assert.True(t, errors.Is(err, errUndo))
+	assert.Contains(t, err.Error(), "rollback of second failed")
+}
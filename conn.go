@@ -0,0 +1,142 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Fd returns the file descriptor of the open real-time clock device.
+// The descriptor remains owned by the RTC and is invalid after Close.
+func (c *RTC) Fd() uintptr {
+	return uintptr(c.fd())
+}
+
+// SyscallConn returns a raw connection to the real-time clock device, so that
+// callers can wait for interrupts in their own poll or epoll loop.
+// The Read method of the returned connection calls its function until it
+// returns true, waiting with poll(2) for the device to become readable
+// whenever it returns false. Closing the RTC interrupts the wait, and Read
+// then returns ErrClosed.
+func (c *RTC) SyscallConn() (syscall.RawConn, error) {
+	rc, err := c.f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	if c.fd() < 0 {
+		return nil, ErrClosed
+	}
+	return &rawConn{rc: rc, cw: &c.conn}, nil
+}
+
+// connWaiter wakes the raw connections of an RTC waiting for the device when
+// it is closed. The waiter is created on first use and closed once the RTC is
+// closed and no connection waits on it any more.
+type connWaiter struct {
+	mu     sync.Mutex
+	w      *waiter
+	users  int
+	closed bool
+}
+
+// acquire returns the waiter, or ErrClosed if the RTC was closed. It must be
+// paired with release.
+func (cw *connWaiter) acquire() (w *waiter, err error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	if cw.closed {
+		return nil, ErrClosed
+	}
+	if cw.w == nil {
+		if cw.w, err = newWaiter(); err != nil {
+			return nil, err
+		}
+	}
+	cw.users++
+	return cw.w, nil
+}
+
+func (cw *connWaiter) release() {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.users--
+	if cw.closed && cw.users == 0 && cw.w != nil {
+		cw.w.close()
+		cw.w = nil
+	}
+}
+
+// close wakes the connections waiting on the waiter, and closes it if there
+// are none.
+func (cw *connWaiter) close() {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.closed = true
+	if cw.w == nil {
+		return
+	}
+	if cw.users > 0 {
+		cw.w.wake()
+		return
+	}
+	cw.w.close()
+	cw.w = nil
+}
+
+// rawConn implements syscall.RawConn for a real-time clock device. The file's
+// own RawConn keeps the descriptor open while f runs, but cannot wait for a
+// blocking descriptor to become readable, so the waiting is done here, along
+// with the waiter that Close wakes.
+type rawConn struct {
+	rc syscall.RawConn
+	cw *connWaiter
+}
+
+func (r *rawConn) Control(f func(fd uintptr)) error {
+	if err := r.rc.Control(f); err != nil {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (r *rawConn) Read(f func(fd uintptr) (done bool)) error {
+	return r.loop(f, unix.POLLIN)
+}
+
+func (r *rawConn) Write(f func(fd uintptr) (done bool)) error {
+	return r.loop(f, unix.POLLOUT)
+}
+
+// loop calls f until it returns true, polling for events between calls.
+func (r *rawConn) loop(f func(fd uintptr) (done bool), events int16) error {
+	w, err := r.cw.acquire()
+	if err != nil {
+		return err
+	}
+	defer r.cw.release()
+	for {
+		var done bool
+		var err error
+		cerr := r.Control(func(fd uintptr) {
+			if done = f(fd); done {
+				return
+			}
+			var ready bool
+			if ready, err = w.waitFor(int(fd), events, -1); err == nil && !ready {
+				err = ErrClosed
+			}
+		})
+		switch {
+		case cerr != nil:
+			return cerr
+		case err != nil:
+			return err
+		case done:
+			return nil
+		}
+	}
+}
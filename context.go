@@ -0,0 +1,57 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"context"
+	"time"
+)
+
+// GetTimeCtx returns the real-time clock's time unless the context is already
+// done. The read itself is a single ioctl that cannot be interrupted.
+func (c *RTC) GetTimeCtx(ctx context.Context) (t time.Time, err error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return c.GetTime()
+}
+
+// WaitForInterruptCtx waits for an interrupt to occur on the real-time clock
+// and returns it. If the context is done first, the context's error is
+// returned, so that long waits can be cancelled when the application shuts
+// down.
+func (c *RTC) WaitForInterruptCtx(ctx context.Context) (e Event, err error) {
+	if err := ctx.Err(); err != nil {
+		return Event{}, err
+	}
+	w, release, err := newContextWaiter(ctx)
+	if err != nil {
+		return Event{}, err
+	}
+	defer release()
+
+	readable, err := w.wait(c.fd(), -1)
+	if err != nil {
+		return Event{}, err
+	}
+	if !readable {
+		return Event{}, ctx.Err()
+	}
+	return c.ReadEvent()
+}
+
+// WaitAlarmCtx blocks until the real-time clock's alarm interrupt occurs or
+// the context is done. The alarm interrupt must be enabled with
+// SetAlarmInterrupt. Other interrupts received while waiting are discarded.
+func (c *RTC) WaitAlarmCtx(ctx context.Context) (err error) {
+	for {
+		e, err := c.WaitForInterruptCtx(ctx)
+		if err != nil {
+			return err
+		}
+		if e.Alarm {
+			return nil
+		}
+	}
+}
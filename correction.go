@@ -0,0 +1,119 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetCorrectionPPB returns the real-time clock's frequency correction in parts
+// per billion, read from the offset sysfs attribute of drivers that support
+// digital trimming. For other devices the returned error wraps
+// ErrNotSupported. See also GetFrequencyCorrection, which also supports
+// RTC_PARAM_CORRECTION.
+func (c *RTC) GetCorrectionPPB() (ppb int64, err error) {
+	path, err := c.offsetPath()
+	if err != nil {
+		return 0, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read real-time clock offset: %w", err)
+	}
+	if ppb, err = strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64); err != nil {
+		return 0, fmt.Errorf("failed to parse real-time clock offset: %w", err)
+	}
+	return ppb, nil
+}
+
+// SetCorrectionPPB sets the real-time clock's frequency correction in parts per
+// billion by writing the offset sysfs attribute, which requires root
+// privileges. A positive correction slows the clock down. The driver rounds the
+// correction to the trimming steps of the chip; read it back with
+// GetCorrectionPPB to get the effective value.
+func (c *RTC) SetCorrectionPPB(ppb int64) (err error) {
+	path, err := c.offsetPath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(strconv.FormatInt(ppb, 10)), 0644); err != nil {
+		return fmt.Errorf("failed to set real-time clock offset: %w", wrapErrno(err))
+	}
+	return nil
+}
+
+// GetFrequencyCorrection returns the real-time clock's aging offset, its
+// frequency correction in parts per billion, with RTC_PARAM_CORRECTION if the
+// device supports it and from the offset sysfs attribute otherwise. If the
+// device supports neither, the returned error wraps ErrNotSupported.
+func (c *RTC) GetFrequencyCorrection() (ppb int64, err error) {
+	ppb, err = c.GetCorrection()
+	if errors.Is(err, ErrNotSupported) {
+		return c.GetCorrectionPPB()
+	}
+	return ppb, err
+}
+
+// SetFrequencyCorrection sets the real-time clock's aging offset, its frequency
+// correction in parts per billion, with RTC_PARAM_CORRECTION if the device
+// supports it and through the offset sysfs attribute otherwise. The correction
+// is the number of ticks added to or removed from the oscillator per billion:
+// a positive correction slows the clock down, making a day pass more slowly,
+// and a negative one speeds it up. The driver rounds the correction to the
+// trimming steps of the chip; read it back with GetFrequencyCorrection to get
+// the effective value. If the device supports neither, the returned error
+// wraps ErrNotSupported.
+func (c *RTC) SetFrequencyCorrection(ppb int64) (err error) {
+	err = c.SetCorrection(ppb)
+	if errors.Is(err, ErrNotSupported) {
+		return c.SetCorrectionPPB(ppb)
+	}
+	return err
+}
+
+// TrimDrift compensates a measured drift of the real-time clock by adjusting
+// its frequency correction. The drift is in parts per billion and positive
+// when the RTC runs fast, as measured by comparing the offsets to a reference
+// clock over a period of time; it is added to the correction, which slows the
+// clock down. It returns the effective correction read back from the device,
+// which the driver rounds to the trimming steps of the chip.
+func (c *RTC) TrimDrift(driftPPB int64) (ppb int64, err error) {
+	cur, err := c.GetFrequencyCorrection()
+	if err != nil {
+		return 0, err
+	}
+	if err := c.SetFrequencyCorrection(cur + driftPPB); err != nil {
+		return 0, err
+	}
+	return c.GetFrequencyCorrection()
+}
+
+// DriftPPB returns the drift of a clock in parts per billion from two offsets
+// to a reference clock measured elapsed apart, for example with
+// MeasureOffset. It is positive when the clock runs fast.
+func DriftPPB(first, second time.Duration, elapsed time.Duration) int64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(second-first) / float64(elapsed) * 1e9)
+}
+
+// offsetPath returns the path of the offset sysfs attribute of the device.
+func (c *RTC) offsetPath() (path string, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return "", err
+	}
+	path = filepath.Join(dir, "offset")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to access real-time clock offset: %w", ErrNotSupported)
+	}
+	return path, nil
+}
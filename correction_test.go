@@ -0,0 +1,15 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriftPPB(t *testing.T) {
+	// Gaining 864µs per day is 10 ppb fast.
+	assert.Equal(t, int64(10), DriftPPB(time.Millisecond, time.Millisecond+864*time.Microsecond, 24*time.Hour))
+	assert.Equal(t, int64(-2000), DriftPPB(0, -2*time.Microsecond, time.Second))
+	assert.Equal(t, int64(0), DriftPPB(0, time.Second, 0))
+}
@@ -0,0 +1,101 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DeadManSwitch keeps the real-time clock's wake alarm armed a fixed timeout
+// in the future for as long as Heartbeat keeps being called.
+// If the application hangs and stops calling Heartbeat, the wake alarm is left
+// to expire, waking the machine if it is suspended, and the recovery hook runs
+// if one was registered. This is useful for remote unattended systems.
+type DeadManSwitch struct {
+	rtc     *RTC
+	timeout time.Duration
+	hook    func()
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// NewDeadManSwitch opens the real-time clock device and arms its wake alarm
+// timeout from now. The timeout must be at least one second, the resolution of
+// the RTC. If hook is not nil, it runs in its own goroutine when the timeout
+// expires without a heartbeat.
+func NewDeadManSwitch(dev string, timeout time.Duration, hook func()) (*DeadManSwitch, error) {
+	if timeout < time.Second {
+		return nil, errors.New("dead man switch timeout must be at least one second")
+	}
+
+	c, err := NewRTC(dev)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never wake before the timeout, even on devices with coarse alarms.
+	c.SetAlarmRounding(AlarmRoundUp)
+	s := &DeadManSwitch{
+		rtc:     c,
+		timeout: timeout,
+		hook:    hook,
+	}
+	if err := s.Heartbeat(); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Heartbeat re-arms the wake alarm timeout from now, and restarts the
+// countdown of the recovery hook.
+func (s *DeadManSwitch) Heartbeat() (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return errors.New("dead man switch stopped")
+	}
+
+	now, err := s.rtc.GetTime()
+	if err != nil {
+		return err
+	}
+	if _, err := s.rtc.SetWakeAlarm(now.Add(s.timeout)); err != nil {
+		return fmt.Errorf("failed to re-arm dead man switch: %w", err)
+	}
+
+	if s.hook != nil {
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.timeout, s.hook)
+		} else {
+			s.timer.Reset(s.timeout)
+		}
+	}
+	return nil
+}
+
+// Stop disarms the switch, cancelling the wake alarm and the recovery hook,
+// and closes the real-time clock device.
+func (s *DeadManSwitch) Stop() (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return nil
+	}
+	s.stopped = true
+
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	err = s.rtc.CancelWakeAlarm()
+	if cerr := s.rtc.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
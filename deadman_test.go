@@ -0,0 +1,38 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadManSwitch(t *testing.T) {
+	fired := make(chan struct{})
+	s, err := NewDeadManSwitch("/dev/rtc", 2*time.Second, func() {
+		close(fired)
+	})
+	require.NoError(t, err)
+	defer s.Stop()
+
+	// Heartbeats keep the switch from firing
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Second)
+		require.NoError(t, s.Heartbeat())
+	}
+	select {
+	case <-fired:
+		t.Fatal("dead man switch fired despite heartbeats")
+	default:
+	}
+
+	select {
+	case <-fired:
+	case <-time.After(4 * time.Second):
+		t.Error("dead man switch did not fire in time")
+	}
+
+	assert.NoError(t, s.Stop())
+	assert.Error(t, s.Heartbeat())
+}
@@ -0,0 +1,134 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ClockInfo describes a real-time clock found by GetClocksInfo.
+type ClockInfo struct {
+	DeviceInfo
+	// HasDevNode is set when DevNode exists. In minimal containers and
+	// initramfs environments the class device may exist without it.
+	HasDevNode bool
+	// WakeupCapable is set when the device can wake the system from sleep.
+	WakeupCapable bool
+	// Hctosys is set when the kernel used the device to initialize the system
+	// clock at boot.
+	Hctosys bool
+	// Features holds the device's capabilities, or nil if the device could
+	// not be opened to read them.
+	Features *DeviceFeatures
+}
+
+// GetClocksInfo returns the real-time clocks in the system along with the
+// information needed to choose between them. It lists the rtc class devices
+// in sysfs, so devices without a node in /dev are included, and opens each
+// device read-only with Open to read its features.
+func GetClocksInfo() (clocks []ClockInfo, err error) {
+	dirs, err := filepath.Glob(filepath.Join(rtcClassDir(), "rtc*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		info := readClockInfo(dir)
+		if c, err := info.Open(WithReadOnly()); err == nil {
+			if features, err := c.Features(); err == nil {
+				info.Features = &features
+			}
+			_ = c.Close()
+		}
+		clocks = append(clocks, info)
+	}
+	return clocks, nil
+}
+
+// readClockInfo reads the information of the class device in the sysfs
+// directory dir, except for its features.
+func readClockInfo(dir string) (info ClockInfo) {
+	info.DeviceInfo = readDeviceInfo(dir)
+	info.Hctosys = readHctosys(dir)
+	info.WakeupCapable, _ = readWakeup(dir)
+	if _, err := os.Stat(info.DevNode); err == nil {
+		info.HasDevNode = true
+	}
+	return info
+}
+
+// Open opens the real-time clock through its device node, or if it has none,
+// through a temporary node created with NewRTCFromSysfs.
+func (info ClockInfo) Open(opts ...Option) (*RTC, error) {
+	if info.HasDevNode {
+		return NewRTC(info.DevNode, opts...)
+	}
+	return NewRTCFromSysfs(info.Name, opts...)
+}
+
+// OpenByName opens the real-time clock whose chip, driver or parent device is
+// named name, such as "ds3231", so that boards with several RTCs can select
+// one regardless of the order in which they were registered. The name is
+// matched case-insensitively against the name attribute of the class device,
+// with or without its "rtc-" prefix and bus address, the driver name, and the
+// name of the parent device. If no clock matches, the returned error wraps
+// os.ErrNotExist.
+func OpenByName(name string, opts ...Option) (*RTC, error) {
+	dir, err := findByName(rtcClassDir(), name)
+	if err != nil {
+		return nil, err
+	}
+	return NewRTC(filepath.Join(devDir(), filepath.Base(dir)), opts...)
+}
+
+// findByName returns the sysfs directory of the class device in classDir
+// matching name.
+func findByName(classDir string, name string) (dir string, err error) {
+	dirs, err := filepath.Glob(filepath.Join(classDir, "rtc*"))
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		info := readDeviceInfo(dir)
+		candidates := []string{info.ChipName, info.Driver}
+		if fields := strings.Fields(info.ChipName); len(fields) > 0 {
+			candidates = append(candidates, fields[0], strings.TrimPrefix(fields[0], "rtc-"))
+		}
+		if b, err := os.ReadFile(filepath.Join(dir, "device", "name")); err == nil {
+			candidates = append(candidates, strings.TrimSpace(string(b)))
+		}
+		for _, c := range candidates {
+			if c != "" && strings.EqualFold(c, name) {
+				return dir, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to find real-time clock named %q: %w", name, os.ErrNotExist)
+}
+
+// DefaultClock returns the device node of the system's primary real-time
+// clock, so that applications need not hard-code "/dev/rtc", which many
+// distributions do not create. It is the RTC that initialized the system clock
+// at boot, or else the target of the /dev/rtc symlink, or else /dev/rtc0. If
+// none exists, the returned error wraps os.ErrNotExist.
+func DefaultClock() (dev string, err error) {
+	return defaultClock(rtcClassDir(), devDir())
+}
+
+// defaultClock returns the primary clock among the class devices in classDir
+// and the device nodes in devDir.
+func defaultClock(classDir string, devDir string) (dev string, err error) {
+	if name, err := hctosysName(classDir); err == nil {
+		return filepath.Join(devDir, name), nil
+	}
+	for _, name := range []string{"rtc", "rtc0"} {
+		dev := filepath.Join(devDir, name)
+		if _, err := os.Stat(dev); err == nil {
+			return dev, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find a real-time clock: %w", os.ErrNotExist)
+}
@@ -0,0 +1,66 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// driftThreshold is how far the alarm must be off before it is re-armed. The
+// RTC time is only read with second resolution, so smaller corrections would
+// chase the truncation rather than drift.
+const driftThreshold = 2 * time.Second
+
+// WithDriftCompensation makes a Timer cross-check the RTC against
+// CLOCK_BOOTTIME every interval while it waits, and re-arm the alarm when the
+// RTC has drifted, so that the Timer expires after the requested duration of
+// system time rather than of RTC time. Corrections are made once the drift
+// reaches two seconds. It is meant for timers of several hours, over which
+// the tens of ppm that RTC crystals commonly drift add up.
+//
+// For timers created with NewTimerAt, the time left until the expiry time on
+// the RTC, or on the system clock with ReferenceSystem, is taken when the
+// alarm is armed, and it is that duration that is measured in system time.
+// The offset between the RTC and the system clock at that point is therefore
+// kept, and only the drift accumulated while waiting is corrected.
+func WithDriftCompensation(interval time.Duration) TimerOption {
+	return func(o *timerOptions) {
+		o.driftEvery = interval
+	}
+}
+
+// driftRemaining returns a function reporting the time left until the alarm
+// of a Timer should fire, total after the reading started, measured with
+// CLOCK_BOOTTIME, which keeps counting while the system is suspended.
+func driftRemaining(started suspendClock, total time.Duration) func() time.Duration {
+	return func() time.Duration {
+		boot, err := clockNow(unix.CLOCK_BOOTTIME)
+		if err != nil {
+			return total
+		}
+		return total - (boot - started.boot)
+	}
+}
+
+// compensateDrift re-arms the alarm a if it is due to fire more than
+// driftThreshold away from remaining from now on the RTC.
+func (c *RTC) compensateDrift(a *timerAlarm, remaining time.Duration) (err error) {
+	now, err := c.GetTime()
+	if err != nil {
+		return err
+	}
+	target := now.Add(remaining)
+	if diff := target.Sub(a.t); diff > -driftThreshold && diff < driftThreshold {
+		return nil
+	}
+	a.t = target
+	if a.wake {
+		_, err = c.SetWakeAlarm(target)
+		return err
+	}
+	a.hop, err = c.armLongAlarm(target)
+	return err
+}
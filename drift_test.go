@@ -0,0 +1,19 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriftRemaining(t *testing.T) {
+	remaining := driftRemaining(readSuspendClock(), time.Hour)
+	assert.InDelta(t, float64(time.Hour), float64(remaining()), float64(time.Second))
+
+	// Time spent waiting counts down
+	started := readSuspendClock()
+	started.boot -= 10 * time.Minute
+	remaining = driftRemaining(started, time.Hour)
+	assert.InDelta(t, float64(50*time.Minute), float64(remaining()), float64(time.Second))
+}
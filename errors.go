@@ -0,0 +1,69 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"syscall"
+)
+
+// ErrNotSupported is returned when the real-time clock or its driver does not
+// support an operation.
+var ErrNotSupported = errors.New("operation not supported by real-time clock")
+
+// ErrTimeout is returned when a real-time clock interrupt does not occur
+// within the given timeout.
+var ErrTimeout = errors.New("timed out waiting for real-time clock interrupt")
+
+// ErrPermission is returned when the caller lacks the permission or
+// capability for an operation, such as CAP_SYS_TIME for setting the time.
+var ErrPermission = errors.New("permission denied by real-time clock")
+
+// ErrBusy is returned when the real-time clock device is in use, for example
+// because another process holds it open.
+var ErrBusy = errors.New("real-time clock is busy")
+
+// ErrClosed is returned when operating on a real-time clock that was closed.
+var ErrClosed = errors.New("real-time clock is closed")
+
+// errnoError is an errno returned by the kernel that also matches the
+// sentinel error of its class with errors.Is. It unwraps to the errno.
+type errnoError struct {
+	errno syscall.Errno
+}
+
+func (e errnoError) Error() string {
+	return e.errno.Error()
+}
+
+func (e errnoError) Unwrap() error {
+	return e.errno
+}
+
+func (e errnoError) Is(target error) bool {
+	switch target {
+	case ErrNotSupported:
+		// EINVAL is left out since it also reports invalid requests, which
+		// must not be mistaken for a missing feature. Probes use
+		// isUnsupported, which accepts it.
+		return e.errno == syscall.ENOTTY || e.errno == syscall.EOPNOTSUPP
+	case ErrPermission:
+		return e.errno == syscall.EPERM || e.errno == syscall.EACCES
+	case ErrBusy:
+		return e.errno == syscall.EBUSY
+	case ErrClosed:
+		return e.errno == syscall.EBADF
+	}
+	return false
+}
+
+// wrapErrno returns err so that it matches the package's sentinel errors if it
+// is an errno, and unchanged otherwise.
+func wrapErrno(err error) error {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return errnoError{errno: errno}
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapErrno(t *testing.T) {
+	for _, tc := range []struct {
+		errno    syscall.Errno
+		sentinel error
+	}{
+		{syscall.ENOTTY, ErrNotSupported},
+		{syscall.EOPNOTSUPP, ErrNotSupported},
+		{syscall.EPERM, ErrPermission},
+		{syscall.EACCES, ErrPermission},
+		{syscall.EBUSY, ErrBusy},
+		{syscall.EBADF, ErrClosed},
+	} {
+		err := fmt.Errorf("failed to do something: %w", wrapErrno(tc.errno))
+		assert.True(t, errors.Is(err, tc.sentinel), "%v is %v", tc.errno, tc.sentinel)
+		assert.True(t, errors.Is(err, tc.errno), "%v unwraps to errno", tc.errno)
+		assert.Equal(t, "failed to do something: "+tc.errno.Error(), err.Error())
+	}
+
+	err := wrapErrno(syscall.EACCES)
+	assert.True(t, errors.Is(err, os.ErrPermission))
+	assert.False(t, errors.Is(err, ErrBusy))
+
+	var errno syscall.Errno
+	assert.True(t, errors.As(err, &errno))
+	assert.Equal(t, syscall.EACCES, errno)
+
+	// EINVAL only reports a missing feature when probing
+	einval := fmt.Errorf("failed to read alarm: %w", wrapErrno(syscall.EINVAL))
+	assert.False(t, errors.Is(einval, ErrNotSupported))
+	assert.True(t, isUnsupported(einval))
+	assert.True(t, isUnsupported(wrapErrno(syscall.ENOTTY)))
+	assert.False(t, isUnsupported(wrapErrno(syscall.EIO)))
+
+	plain := errors.New("plain")
+	assert.Equal(t, plain, wrapErrno(plain))
+}
+
+func TestFeatureUnavailableErrorIs(t *testing.T) {
+	err := &FeatureUnavailableError{Feature: FeatureEpoch, Reason: "test", Err: syscall.ENOTTY}
+	assert.True(t, errors.Is(err, ErrNotSupported))
+	assert.True(t, errors.Is(err, syscall.ENOTTY))
+}
@@ -0,0 +1,64 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Event is an interrupt event read from a real-time clock device.
+type Event struct {
+	// Alarm is set when the alarm interrupt occurred.
+	Alarm bool
+	// Update is set when the update (once per second) interrupt occurred.
+	Update bool
+	// Periodic is set when the periodic interrupt occurred.
+	Periodic bool
+	// Count is the number of interrupts that occurred since the previous read.
+	Count uint32
+}
+
+// parseEvent decodes the value read from a real-time clock device.
+func parseEvent(buf []byte) Event {
+	// buf[0] = bit mask encoding the types of interrupt that occurred.
+	// buf[1:3] = number of interrupts since last read
+	r := binary.LittleEndian.Uint32(buf)
+	return Event{
+		Alarm:    r&unix.RTC_AF != 0,
+		Update:   r&unix.RTC_UF != 0,
+		Periodic: r&unix.RTC_PF != 0,
+		Count:    r >> 8,
+	}
+}
+
+// ReadEvent blocks until an interrupt occurs on the real-time clock and
+// returns it. The interrupts of interest must be enabled first, for example
+// with SetUpdateInterrupt.
+func (c *RTC) ReadEvent() (e Event, err error) {
+	buf := make([]byte, 4)
+	if _, err := syscall.Read(c.fd(), buf); err != nil {
+		return Event{}, fmt.Errorf("failed to read real-time clock interrupt: %w", wrapErrno(err))
+	}
+	return parseEvent(buf), nil
+}
+
+// WaitForInterrupt waits up to timeout for an interrupt to occur on the
+// real-time clock and returns it. Unlike ReadEvent, the wait is bounded: if no
+// interrupt occurs in time, the returned error wraps ErrTimeout. A negative
+// timeout waits indefinitely.
+func (c *RTC) WaitForInterrupt(timeout time.Duration) (e Event, err error) {
+	readable, err := pollReadable(c.fd(), timeout)
+	if err != nil {
+		return Event{}, err
+	}
+	if !readable {
+		return Event{}, fmt.Errorf("%w after %v", ErrTimeout, timeout)
+	}
+	return c.ReadEvent()
+}
@@ -0,0 +1,13 @@
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEvent(t *testing.T) {
+	assert.Equal(t, Event{Alarm: true, Count: 1}, parseEvent([]byte{0xA0, 0x01, 0x00, 0x00}))
+	assert.Equal(t, Event{Update: true, Count: 1}, parseEvent([]byte{0x90, 0x01, 0x00, 0x00}))
+	assert.Equal(t, Event{Periodic: true, Count: 0x0203}, parseEvent([]byte{0xC0, 0x03, 0x02, 0x00}))
+}
@@ -91,9 +91,11 @@ func ExampleGetAlarm() {
 }
 
 func ExampleSetAlarm() {
-	if err := rtc.SetAlarm("/dev/rtc", time.Now().Add(time.Minute)); err != nil {
+	t, err := rtc.SetAlarm("/dev/rtc", time.Now().Add(time.Minute))
+	if err != nil {
 		panic(err)
 	}
+	fmt.Printf("Alarm set for: %v\n", t)
 }
 
 func ExampleSetAlarmInterrupt() {
@@ -115,3 +117,68 @@ func ExampleSetFrequency() {
 		panic(err)
 	}
 }
+
+func ExampleFindClock() {
+	info, err := rtc.FindClock(rtc.HasWakeAlarm(), rtc.HasMinFrequency(1024))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Using %s (%s)\n", info.DevNode, info.ChipName)
+}
+
+func ExampleWithRTC() {
+	err := rtc.WithRTC("/dev/rtc", func(c *rtc.RTC) error {
+		t, err := c.GetTime()
+		if err != nil {
+			return err
+		}
+		_, err = c.SetWakeAlarm(t.Add(time.Hour))
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func ExampleAfter() {
+	ch, err := rtc.After("/dev/rtc", time.Minute)
+	if err != nil {
+		panic(err)
+	}
+	alarm := <-ch
+	fmt.Printf("Alarm.  Time:%v\n", alarm.Time)
+}
+
+func ExampleSharedRTC_NewTimer() {
+	c, err := rtc.OpenShared("/dev/rtc")
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	// Both timers share the device's single alarm
+	short, err := c.NewTimer(time.Minute)
+	if err != nil {
+		panic(err)
+	}
+	long, err := c.NewTimer(time.Hour)
+	if err != nil {
+		panic(err)
+	}
+	defer long.Stop()
+
+	alarm := <-short.C
+	fmt.Printf("Alarm.  Time:%v\n", alarm.Time)
+}
+
+func ExampleNewScheduler() {
+	s, err := rtc.NewScheduler("/dev/rtc", "every day at 03:00")
+	if err != nil {
+		panic(err)
+	}
+	defer s.Stop()
+
+	for o := range s.C {
+		fmt.Printf("Occurrence.  Scheduled:%v  Time:%v\n", o.Scheduled, o.Time)
+	}
+}
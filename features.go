@@ -0,0 +1,99 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Features returns the capabilities of the real-time clock device so that
+// applications can branch on them up front.
+// On kernels that support RTC_PARAM_GET the features reported by the driver
+// are returned. Otherwise they are probed: the alarm with a read of the
+// standard alarm, the alarm resolution with AlarmResolution and the frequency
+// correction with the offset sysfs attribute. Older kernels emulate update
+// interrupts with the alarm, so DeviceFeatureUpdateInterrupt follows
+// DeviceFeatureAlarm.
+func (c *RTC) Features() (features DeviceFeatures, err error) {
+	if c.CheckFeature(FeatureParam) == nil {
+		return c.GetParamFeatures()
+	}
+
+	if _, err := c.GetAlarm(); err == nil {
+		features |= DeviceFeatureAlarm | DeviceFeatureUpdateInterrupt
+	} else if !isUnsupported(err) {
+		return 0, err
+	}
+	if features.Has(DeviceFeatureAlarm) {
+		resolution, err := c.AlarmResolution()
+		if err != nil {
+			return 0, err
+		}
+		switch resolution {
+		case time.Minute:
+			features |= DeviceFeatureAlarmResMinute
+		case 2 * time.Second:
+			features |= DeviceFeatureAlarmRes2s
+		}
+	}
+	if dir, err := c.sysfsDir(); err == nil {
+		if _, err := os.Stat(filepath.Join(dir, "offset")); err == nil {
+			features |= DeviceFeatureCorrection
+		}
+	}
+	return features, nil
+}
+
+// SupportsAlarm reports whether the real-time clock has an alarm.
+func (c *RTC) SupportsAlarm() (supported bool, err error) {
+	features, err := c.Features()
+	if err != nil {
+		return false, err
+	}
+	return features.Has(DeviceFeatureAlarm), nil
+}
+
+// SupportsWakeAlarm reports whether the real-time clock has an alarm that can
+// be programmed with RTC_WKALM_SET.
+func (c *RTC) SupportsWakeAlarm() (supported bool, err error) {
+	if supported, err = c.SupportsAlarm(); !supported || err != nil {
+		return false, err
+	}
+	err = c.CheckFeature(FeatureWakeAlarm)
+	var unavailable *FeatureUnavailableError
+	if errors.As(err, &unavailable) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// SupportsPeriodic reports whether the real-time clock supports periodic
+// interrupts, probed with RTC_IRQP_READ.
+func (c *RTC) SupportsPeriodic() (supported bool, err error) {
+	f := new(uint)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_IRQP_READ, uintptr(unsafe.Pointer(f))); errno != 0 {
+		if isUnsupported(errno) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to probe real-time clock periodic interrupt: %w", wrapErrno(errno))
+	}
+	return true, nil
+}
+
+// isUnsupported reports whether err is an errno returned by drivers that do
+// not implement an ioctl: one matching ErrNotSupported, or EINVAL, which the
+// kernel also returns for features a device lacks, such as reading the alarm
+// of a device without one. It is only meant for probing, since EINVAL
+// otherwise reports an invalid request.
+func isUnsupported(err error) bool {
+	return errors.Is(wrapErrno(err), ErrNotSupported) || errors.Is(err, syscall.EINVAL)
+}
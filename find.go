@@ -0,0 +1,87 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+)
+
+// ClockFilter selects real-time clocks in FindClock. It is called with the
+// information of a clock and the opened device.
+type ClockFilter func(info ClockInfo, c *RTC) bool
+
+// FindClock returns the first real-time clock, in the order of GetClocksInfo,
+// accepted by all of the given filters. Each device is opened to run the
+// filters, read-write if permitted and read-only otherwise. If no clock
+// matches, the returned error wraps os.ErrNotExist.
+func FindClock(filters ...ClockFilter) (info ClockInfo, err error) {
+	clocks, err := GetClocksInfo()
+	if err != nil {
+		return ClockInfo{}, err
+	}
+	for _, info := range clocks {
+		c, err := info.Open()
+		if err != nil {
+			if c, err = info.Open(WithReadOnly()); err != nil {
+				continue
+			}
+		}
+		ok := true
+		for _, filter := range filters {
+			if ok = filter(info, c); !ok {
+				break
+			}
+		}
+		_ = c.Close()
+		if ok {
+			return info, nil
+		}
+	}
+	return ClockInfo{}, fmt.Errorf("failed to find a matching real-time clock: %w", os.ErrNotExist)
+}
+
+// HasFeatures selects clocks that report all of the given features.
+func HasFeatures(features DeviceFeatures) ClockFilter {
+	return func(info ClockInfo, c *RTC) bool {
+		return info.Features != nil && info.Features.Has(features)
+	}
+}
+
+// HasWakeAlarm selects clocks whose alarm can be programmed with
+// RTC_WKALM_SET.
+func HasWakeAlarm() ClockFilter {
+	return func(info ClockInfo, c *RTC) bool {
+		ok, err := c.SupportsWakeAlarm()
+		return ok && err == nil
+	}
+}
+
+// IsHctosys selects the clock that initialized the system clock at boot.
+func IsHctosys() ClockFilter {
+	return func(info ClockInfo, c *RTC) bool {
+		return info.Hctosys
+	}
+}
+
+// HasMinFrequency selects clocks whose periodic interrupt can run at the
+// given frequency. The frequency is probed by setting it and restoring the
+// previous one, which requires the device to be writable, and frequencies
+// above 64 Hz typically require root privileges.
+func HasMinFrequency(frequency uint) ClockFilter {
+	return func(info ClockInfo, c *RTC) bool {
+		prev, err := c.GetFrequency()
+		if err != nil {
+			return false
+		}
+		if prev >= frequency {
+			return true
+		}
+		if err := c.SetFrequency(frequency); err != nil {
+			return false
+		}
+		_ = c.SetFrequency(prev)
+		return true
+	}
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAlarmConflict is returned when programming an alarm would overwrite a
+// different alarm that is still pending.
+var ErrAlarmConflict = errors.New("real-time clock has a different alarm pending")
+
+// AlarmGuard controls how SetWakeAlarmGuarded treats a wake alarm that is
+// already programmed. The zero value refuses to overwrite a different pending
+// alarm.
+type AlarmGuard struct {
+	// Force overwrites any pending alarm without checking.
+	Force bool
+	// OnConflict, if not nil, is called with the pending and requested alarm
+	// times when they differ. Returning nil overwrites the pending alarm, for
+	// example after logging a warning. Returning an error leaves the pending
+	// alarm untouched and the error is returned to the caller.
+	OnConflict func(pending, requested time.Time) error
+}
+
+// SetWakeAlarmGuarded sets the real-time clock's wake alarm time and returns
+// the effective alarm time, like SetWakeAlarm, unless a different wake alarm
+// is pending. An alarm is pending if it is enabled and its time has not yet
+// passed. This prevents silently losing a wake programmed by another tool
+// using the same RTC. Without an OnConflict callback, a conflict returns an
+// error wrapping ErrAlarmConflict.
+func (c *RTC) SetWakeAlarmGuarded(t time.Time, g AlarmGuard) (effective time.Time, err error) {
+	if !g.Force {
+		if err := c.checkWakeAlarmConflict(t, g); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return c.SetWakeAlarm(t)
+}
+
+func (c *RTC) checkWakeAlarmConflict(t time.Time, g AlarmGuard) (err error) {
+	a, err := c.WakeAlarm()
+	if err != nil {
+		return err
+	}
+	pending := a.Time
+	requested, err := c.effectiveAlarm(t)
+	if err != nil {
+		return err
+	}
+	if !a.Enabled || pending.Equal(timeRtc{Time: requested}.truncated()) {
+		return nil
+	}
+
+	now, err := c.GetTime()
+	if err != nil {
+		return err
+	}
+	if !pending.After(now) {
+		return nil
+	}
+
+	if g.OnConflict != nil {
+		return g.OnConflict(pending, t)
+	}
+	return fmt.Errorf("%w: alarm at %v would be replaced by %v", ErrAlarmConflict, pending, t)
+}
@@ -0,0 +1,45 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetWakeAlarmGuarded(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	now, err := c.GetTime()
+	require.NoError(t, err)
+	first := now.Add(time.Hour)
+	second := now.Add(2 * time.Hour)
+
+	_, err = c.SetWakeAlarm(first)
+	require.NoError(t, err)
+	defer c.CancelWakeAlarm()
+
+	// Re-arming the same alarm is not a conflict
+	_, err = c.SetWakeAlarmGuarded(first, AlarmGuard{})
+	assert.NoError(t, err)
+
+	_, err = c.SetWakeAlarmGuarded(second, AlarmGuard{})
+	assert.True(t, errors.Is(err, ErrAlarmConflict))
+
+	var conflict time.Time
+	_, err = c.SetWakeAlarmGuarded(second, AlarmGuard{
+		OnConflict: func(pending, requested time.Time) error {
+			conflict = pending
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, first.Equal(conflict))
+
+	_, err = c.SetWakeAlarmGuarded(first, AlarmGuard{Force: true})
+	assert.NoError(t, err)
+}
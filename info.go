@@ -0,0 +1,49 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DeviceInfo identifies a real-time clock device.
+type DeviceInfo struct {
+	// Name is the class device name, such as "rtc0".
+	Name string
+	// ChipName is the name reported by the driver, such as "rtc_cmos".
+	ChipName string
+	// Driver is the kernel driver bound to the parent device, if any.
+	Driver string
+	// SysfsPath is the sysfs directory of the device.
+	SysfsPath string
+	// DevNode is the device node, such as "/dev/rtc0".
+	DevNode string
+}
+
+// Info returns information identifying the real-time clock device, so that
+// logs and user interfaces can show which chip is in use.
+func (c *RTC) Info() (info DeviceInfo, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+	return readDeviceInfo(dir), nil
+}
+
+// readDeviceInfo reads the information of the class device in the sysfs
+// directory dir.
+func readDeviceInfo(dir string) (info DeviceInfo) {
+	info.Name = filepath.Base(dir)
+	info.SysfsPath = dir
+	info.DevNode = filepath.Join(devDir(), info.Name)
+	if b, err := os.ReadFile(filepath.Join(dir, "name")); err == nil {
+		info.ChipName = strings.TrimSpace(string(b))
+	}
+	if driver, err := os.Readlink(filepath.Join(dir, "device", "driver")); err == nil {
+		info.Driver = filepath.Base(driver)
+	}
+	return info
+}
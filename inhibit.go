@@ -0,0 +1,86 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SuspendInhibitor prevents the system from suspending while a Timer that
+// cannot wake it is pending.
+type SuspendInhibitor interface {
+	// Inhibit takes a lock preventing suspend, giving why as the reason, and
+	// returns a function releasing it.
+	Inhibit(why string) (release func() error, err error)
+}
+
+// WithSuspendInhibitor makes a Timer hold a lock from i while it is pending,
+// so that the system does not sleep through an alarm that cannot wake it.
+// Timers created with NewWakeTimerAt do not take the lock.
+func WithSuspendInhibitor(i SuspendInhibitor) TimerOption {
+	return func(o *timerOptions) {
+		o.inhibitor = i
+	}
+}
+
+// LogindInhibitor takes systemd-logind sleep inhibitor locks by running
+// systemd-inhibit, which holds the lock until its standard input is closed.
+// The lock is therefore also released if the process exits. Inhibit returns
+// once logind has granted the lock, and fails if it cannot be taken, for
+// example on systems without logind. If the lock is lost before it is
+// released, for example because systemd-inhibit was killed, the release
+// function returns an error.
+type LogindInhibitor struct {
+	// Who names the application holding the lock. If empty, the name of the
+	// executable is used.
+	Who string
+}
+
+// Inhibit takes a sleep inhibitor lock in block mode.
+func (l LogindInhibitor) Inhibit(why string) (release func() error, err error) {
+	who := l.Who
+	if who == "" {
+		who = filepath.Base(os.Args[0])
+	}
+	// systemd-inhibit only runs the shell once it holds the lock, so the line
+	// the shell prints confirms it.
+	cmd := exec.Command("systemd-inhibit", "--what=sleep", "--mode=block", "--who="+who, "--why="+why,
+		"sh", "-c", "echo; exec cat >/dev/null")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take suspend inhibitor lock: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take suspend inhibitor lock: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to take suspend inhibitor lock: %w", err)
+	}
+	if _, err := bufio.NewReader(stdout).ReadString('\n'); err != nil {
+		_ = stdin.Close()
+		if werr := cmd.Wait(); werr != nil {
+			err = werr
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to take suspend inhibitor lock: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("failed to take suspend inhibitor lock: %w", err)
+	}
+	return func() error {
+		_ = stdin.Close()
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("failed to hold suspend inhibitor lock: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import "fmt"
+
+// GetAlarmInterrupt reports whether the real-time clock's alarm interrupt is
+// enabled.
+func (c *RTC) GetAlarmInterrupt() (enabled bool, err error) {
+	// The enabled flag of the wake alarm reflects the alarm interrupt.
+	a, err := c.WakeAlarm()
+	if err != nil {
+		return false, err
+	}
+	return a.Enabled, nil
+}
+
+// GetUpdateInterrupt reports whether the real-time clock's update interrupt is
+// enabled. The kernel only reports this state in /proc/driver/rtc for the RTC
+// that set the system clock at boot; for other devices the returned error
+// wraps ErrNotSupported.
+func (c *RTC) GetUpdateInterrupt() (enabled bool, err error) {
+	update, _ := c.procInterrupts()
+	if update == nil {
+		return false, fmt.Errorf("failed to read real-time clock update interrupt state: %w", ErrNotSupported)
+	}
+	return *update, nil
+}
+
+// GetPeriodicInterrupt reports whether the real-time clock's periodic
+// interrupt is enabled. The kernel only reports this state in
+// /proc/driver/rtc for the RTC that set the system clock at boot; for other
+// devices the returned error wraps ErrNotSupported.
+func (c *RTC) GetPeriodicInterrupt() (enabled bool, err error) {
+	_, periodic := c.procInterrupts()
+	if periodic == nil {
+		return false, fmt.Errorf("failed to read real-time clock periodic interrupt state: %w", ErrNotSupported)
+	}
+	return *periodic, nil
+}
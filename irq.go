@@ -0,0 +1,90 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procInterruptsPath returns the location of /proc/interrupts, which lists
+// the interrupt counts of each IRQ line per CPU.
+func procInterruptsPath() string {
+	return procPath("interrupts")
+}
+
+// InterruptCount returns the number of interrupts delivered on the real-time
+// clock's IRQ line since boot, summed over all CPUs, from /proc/interrupts.
+// Comparing two counts taken a known time apart verifies that periodic
+// interrupts are delivered at the configured rate. The line is found by the
+// IRQ number of the parent device, or else by the names the driver registers
+// it under. If it cannot be found, the returned error wraps ErrNotSupported.
+func (c *RTC) InterruptCount() (count uint64, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return 0, err
+	}
+	info := readDeviceInfo(dir)
+	var irq string
+	if b, err := os.ReadFile(filepath.Join(dir, "device", "irq")); err == nil {
+		irq = strings.TrimSpace(string(b))
+	}
+	b, err := os.ReadFile(procInterruptsPath())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", procInterruptsPath(), err)
+	}
+	count, ok := parseInterruptCount(string(b), irq, []string{info.Name, info.ChipName, info.Driver})
+	if !ok {
+		return 0, fmt.Errorf("failed to find real-time clock interrupt in %s: %w", procInterruptsPath(), ErrNotSupported)
+	}
+	return count, nil
+}
+
+// parseInterruptCount returns the total count of the IRQ line in the contents
+// of /proc/interrupts with the given number, or if irq is empty, the line
+// whose handlers are registered under one of the given names.
+func parseInterruptCount(s string, irq string, names []string) (count uint64, ok bool) {
+	lines := strings.Split(s, "\n")
+	// The header names one column per CPU.
+	cpus := len(strings.Fields(lines[0]))
+	for _, l := range lines[1:] {
+		fields := strings.Fields(l)
+		if len(fields) < 1+cpus {
+			continue
+		}
+		if irq != "" {
+			if strings.TrimSuffix(fields[0], ":") != irq {
+				continue
+			}
+		} else if !hasAction(fields[1+cpus:], names) {
+			continue
+		}
+		for _, f := range fields[1 : 1+cpus] {
+			n, err := strconv.ParseUint(f, 10, 64)
+			if err != nil {
+				return 0, false
+			}
+			count += n
+		}
+		return count, true
+	}
+	return 0, false
+}
+
+// hasAction reports whether the trailing fields of a /proc/interrupts line,
+// which end with the comma separated handler names, contain one of names.
+func hasAction(fields []string, names []string) bool {
+	for _, f := range fields {
+		f = strings.TrimSuffix(f, ",")
+		for _, name := range names {
+			if name != "" && f == name {
+				return true
+			}
+		}
+	}
+	return false
+}
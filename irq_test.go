@@ -0,0 +1,30 @@
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProcInterrupts = `           CPU0       CPU1
+  0:         44          0   IO-APIC   2-edge      timer
+  8:          1        127   IO-APIC   8-edge      rtc0
+ 45:        100          5   PCI-MSI 327680-edge      xhci_hcd, rtc-ds1307
+NMI:          0          0   Non-maskable interrupts
+ERR:          0
+`
+
+func TestParseInterruptCount(t *testing.T) {
+	count, ok := parseInterruptCount(testProcInterrupts, "8", nil)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(128), count)
+
+	count, ok = parseInterruptCount(testProcInterrupts, "", []string{"rtc1", "rtc-ds1307"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(105), count)
+
+	_, ok = parseInterruptCount(testProcInterrupts, "9", nil)
+	assert.False(t, ok)
+	_, ok = parseInterruptCount(testProcInterrupts, "", []string{"", "rtc1"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,386 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrJobExists is returned when submitting a job whose ID is already queued.
+var ErrJobExists = errors.New("job already queued")
+
+// ErrJobNotFound is returned when a job ID is not queued.
+var ErrJobNotFound = errors.New("job not queued")
+
+// Job is a unit of work queued on a JobScheduler.
+type Job struct {
+	// ID identifies the job while it is queued.
+	ID string
+	// Earliest is the time before which the job must not run.
+	Earliest time.Time
+	// Latest is the time by which the job must run. If zero, it is Earliest.
+	// A window lets the job run together with others to save wakeups.
+	Latest time.Time
+	// Priority orders jobs that run together, highest first.
+	Priority int
+}
+
+// JobEvent is delivered by a JobScheduler when a job is due.
+type JobEvent struct {
+	Job Job
+	// Alarm is the alarm that made the job due.
+	Alarm Alarm
+}
+
+// jobEntry is a queued job.
+type jobEntry struct {
+	job   Job
+	seq   uint64
+	index int
+}
+
+// jobHeap orders jobs by the end of their window, then by priority and by
+// submission. It implements heap.Interface.
+type jobHeap []*jobEntry
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	if !a.job.Latest.Equal(b.job.Latest) {
+		return a.job.Latest.Before(b.job.Latest)
+	}
+	if a.job.Priority != b.job.Priority {
+		return a.job.Priority > b.job.Priority
+	}
+	return a.seq < b.seq
+}
+
+func (h jobHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *jobHeap) Push(x interface{}) {
+	e := x.(*jobEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
+
+// jobQueue holds the queued jobs by ID and in the order they fall due.
+type jobQueue struct {
+	byID  map[string]*jobEntry
+	order jobHeap
+	seq   uint64
+}
+
+// validJob checks job and fills in a zero Latest.
+func validJob(job Job) (Job, error) {
+	if job.ID == "" {
+		return job, errors.New("invalid job: empty ID")
+	}
+	if job.Earliest.IsZero() {
+		return job, fmt.Errorf("invalid job %q: no earliest time", job.ID)
+	}
+	if job.Latest.IsZero() {
+		job.Latest = job.Earliest
+	}
+	if job.Latest.Before(job.Earliest) {
+		return job, fmt.Errorf("invalid job %q: latest time %v before earliest time %v", job.ID, job.Latest, job.Earliest)
+	}
+	return job, nil
+}
+
+func (q *jobQueue) add(job Job) (err error) {
+	if job, err = validJob(job); err != nil {
+		return err
+	}
+	if _, ok := q.byID[job.ID]; ok {
+		return fmt.Errorf("failed to submit job %q: %w", job.ID, ErrJobExists)
+	}
+	if q.byID == nil {
+		q.byID = make(map[string]*jobEntry)
+	}
+	q.seq++
+	e := &jobEntry{job: job, seq: q.seq}
+	q.byID[job.ID] = e
+	heap.Push(&q.order, e)
+	return nil
+}
+
+func (q *jobQueue) remove(id string) bool {
+	e, ok := q.byID[id]
+	if !ok {
+		return false
+	}
+	delete(q.byID, id)
+	heap.Remove(&q.order, e.index)
+	return true
+}
+
+func (q *jobQueue) reschedule(id string, earliest, latest time.Time) (err error) {
+	e, ok := q.byID[id]
+	if !ok {
+		return fmt.Errorf("failed to reschedule job %q: %w", id, ErrJobNotFound)
+	}
+	job := e.job
+	job.Earliest, job.Latest = earliest, latest
+	if job, err = validJob(job); err != nil {
+		return err
+	}
+	e.job = job
+	heap.Fix(&q.order, e.index)
+	return nil
+}
+
+// list returns the queued jobs in the order they fall due.
+func (q *jobQueue) list() []Job {
+	entries := append(jobHeap(nil), q.order...)
+	sort.Slice(entries, func(i, j int) bool { return entries.Less(i, j) })
+	jobs := make([]Job, len(entries))
+	for i, e := range entries {
+		jobs[i] = e.job
+	}
+	return jobs
+}
+
+// next returns the time the alarm must fire for the earliest window to end.
+func (q *jobQueue) next() (t time.Time, ok bool) {
+	if len(q.order) == 0 {
+		return time.Time{}, false
+	}
+	return q.order[0].job.Latest, true
+}
+
+// popDue removes and returns the jobs whose window has started by limit,
+// highest priority first.
+func (q *jobQueue) popDue(limit time.Time) []Job {
+	var due []*jobEntry
+	for _, e := range q.order {
+		if !e.job.Earliest.After(limit) {
+			due = append(due, e)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].job.Priority != due[j].job.Priority {
+			return due[i].job.Priority > due[j].job.Priority
+		}
+		return jobHeap(due).Less(i, j)
+	})
+	jobs := make([]Job, len(due))
+	for i, e := range due {
+		jobs[i] = e.job
+		q.remove(e.job.ID)
+	}
+	return jobs
+}
+
+// JobScheduler queues jobs with time windows and priorities, and runs them
+// off a single hardware alarm. The alarm is armed for the end of the earliest
+// window; when it fires, every job whose window has started is delivered, so
+// that jobs with overlapping windows share a wakeup.
+type JobScheduler struct {
+	// C receives the jobs as they fall due.
+	C <-chan JobEvent
+	// Err receives errors arming the alarm. The alarm is not re-armed until
+	// the queue changes.
+	Err <-chan error
+
+	dev  string
+	opts []TimerOption
+
+	mu sync.Mutex
+	q  jobQueue
+
+	ch     chan JobEvent
+	errc   chan error
+	kick   chan struct{}
+	done   chan struct{}
+	close  sync.Once
+	exited chan struct{}
+}
+
+// NewJobScheduler starts a JobScheduler on the device. The options configure
+// the Timer armed for the next due job.
+func NewJobScheduler(dev string, opts ...TimerOption) *JobScheduler {
+	ch := make(chan JobEvent, 16)
+	errc := make(chan error, 1)
+	s := &JobScheduler{
+		C:      ch,
+		Err:    errc,
+		dev:    dev,
+		opts:   opts,
+		ch:     ch,
+		errc:   errc,
+		kick:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// Submit queues a job. It returns an error wrapping ErrJobExists if a job
+// with the same ID is queued.
+func (s *JobScheduler) Submit(job Job) (err error) {
+	s.mu.Lock()
+	err = s.q.add(job)
+	s.mu.Unlock()
+	if err == nil {
+		s.changed()
+	}
+	return err
+}
+
+// Cancel removes a queued job. It reports whether the job was queued.
+func (s *JobScheduler) Cancel(id string) bool {
+	s.mu.Lock()
+	removed := s.q.remove(id)
+	s.mu.Unlock()
+	if removed {
+		s.changed()
+	}
+	return removed
+}
+
+// Reschedule moves the window of a queued job. It returns an error wrapping
+// ErrJobNotFound if the job is not queued.
+func (s *JobScheduler) Reschedule(id string, earliest, latest time.Time) (err error) {
+	s.mu.Lock()
+	err = s.q.reschedule(id, earliest, latest)
+	s.mu.Unlock()
+	if err == nil {
+		s.changed()
+	}
+	return err
+}
+
+// Jobs returns the queued jobs in the order they fall due.
+func (s *JobScheduler) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.q.list()
+}
+
+// Close stops the JobScheduler and disarms its alarm. Queued jobs are
+// discarded. It does not close C.
+func (s *JobScheduler) Close() (err error) {
+	s.close.Do(func() {
+		close(s.done)
+	})
+	<-s.exited
+	return nil
+}
+
+// changed wakes the loop to re-arm the alarm for the changed queue.
+func (s *JobScheduler) changed() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+// loop keeps a Timer armed for the next due job and delivers the due jobs
+// when it fires.
+func (s *JobScheduler) loop() {
+	defer close(s.exited)
+	var timer *Timer
+	var armed time.Time
+	failed := false
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		s.mu.Lock()
+		next, ok := s.q.next()
+		s.mu.Unlock()
+
+		if timer != nil && (!ok || !next.Equal(armed)) {
+			timer.Stop()
+			timer = nil
+		}
+		if now := time.Now(); ok && !next.After(now) {
+			// The alarm cannot be armed in the past.
+			if !s.deliver(now, Alarm{Time: now}) {
+				return
+			}
+			continue
+		}
+		if ok && timer == nil && !failed {
+			var err error
+			if timer, err = NewTimerAt(s.dev, next, s.opts...); err != nil {
+				s.fail(err)
+				failed = true
+			}
+			armed = next
+		}
+
+		var alarms <-chan Alarm
+		var errs <-chan error
+		if timer != nil {
+			alarms, errs = timer.C, timer.Err
+		}
+		select {
+		case <-s.done:
+			return
+		case <-s.kick:
+			failed = false
+		case err := <-errs:
+			timer = nil
+			s.fail(err)
+			failed = true
+		case a := <-alarms:
+			timer = nil
+			limit := time.Now()
+			if armed.After(limit) {
+				limit = armed
+			}
+			if !s.deliver(limit, a) {
+				return
+			}
+		}
+	}
+}
+
+// deliver sends the jobs whose window has started by limit on C. It returns
+// false if the JobScheduler was closed meanwhile.
+func (s *JobScheduler) deliver(limit time.Time, a Alarm) bool {
+	s.mu.Lock()
+	due := s.q.popDue(limit)
+	s.mu.Unlock()
+	for _, job := range due {
+		select {
+		case s.ch <- JobEvent{Job: job, Alarm: a}:
+		case <-s.done:
+			return false
+		}
+	}
+	return true
+}
+
+// fail delivers err on the Err channel, unless a previous error is pending.
+func (s *JobScheduler) fail(err error) {
+	select {
+	case s.errc <- err:
+	default:
+	}
+}
@@ -0,0 +1,89 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jobIDs(jobs []Job) []string {
+	ids := make([]string, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+	return ids
+}
+
+func TestJobQueue(t *testing.T) {
+	base := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	var q jobQueue
+	require.NoError(t, q.add(Job{ID: "backup", Earliest: base.Add(time.Hour), Latest: base.Add(3 * time.Hour)}))
+	require.NoError(t, q.add(Job{ID: "sync", Earliest: base.Add(2 * time.Hour)}))
+	require.NoError(t, q.add(Job{ID: "report", Earliest: base.Add(2 * time.Hour), Priority: 5}))
+	require.NoError(t, q.add(Job{ID: "cleanup", Earliest: base.Add(4 * time.Hour)}))
+
+	assert.True(t, errors.Is(q.add(Job{ID: "sync", Earliest: base}), ErrJobExists))
+	assert.Error(t, q.add(Job{ID: "bad", Earliest: base, Latest: base.Add(-time.Second)}))
+	assert.Error(t, q.add(Job{Earliest: base}))
+
+	// Jobs fall due by the end of their window, then by priority
+	assert.Equal(t, []string{"report", "sync", "backup", "cleanup"}, jobIDs(q.list()))
+	next, ok := q.next()
+	require.True(t, ok)
+	assert.True(t, base.Add(2*time.Hour).Equal(next))
+
+	require.NoError(t, q.reschedule("cleanup", base, base.Add(time.Hour)))
+	assert.True(t, errors.Is(q.reschedule("missing", base, base), ErrJobNotFound))
+	assert.Equal(t, []string{"cleanup", "report", "sync", "backup"}, jobIDs(q.list()))
+
+	// Every job whose window has started runs together, by priority
+	assert.Equal(t, []string{"report", "cleanup", "sync", "backup"}, jobIDs(q.popDue(base.Add(2*time.Hour))))
+	_, ok = q.next()
+	assert.False(t, ok)
+
+	require.NoError(t, q.add(Job{ID: "sync", Earliest: base}))
+	assert.True(t, q.remove("sync"))
+	assert.False(t, q.remove("sync"))
+}
+
+func TestJobSchedulerPastJob(t *testing.T) {
+	s := NewJobScheduler("/dev/null")
+	defer s.Close()
+
+	// Jobs already due run without arming the alarm
+	require.NoError(t, s.Submit(Job{ID: "late", Earliest: time.Now().Add(-time.Minute)}))
+	select {
+	case e := <-s.C:
+		assert.Equal(t, "late", e.Job.ID)
+	case <-time.After(time.Second):
+		t.Fatal("due job was not delivered")
+	}
+	assert.Empty(t, s.Jobs())
+	assert.False(t, s.Cancel("late"))
+}
+
+func TestJobScheduler(t *testing.T) {
+	s := NewJobScheduler("/dev/rtc")
+	defer s.Close()
+
+	now := time.Now()
+	require.NoError(t, s.Submit(Job{ID: "a", Earliest: now.Add(time.Second), Latest: now.Add(2 * time.Second)}))
+	require.NoError(t, s.Submit(Job{ID: "b", Earliest: now.Add(1500 * time.Millisecond), Latest: now.Add(time.Hour), Priority: 1}))
+
+	// b's window has started when a's ends, so both share the alarm
+	var ids []string
+	for len(ids) < 2 {
+		select {
+		case e := <-s.C:
+			ids = append(ids, e.Job.ID)
+		case err := <-s.Err:
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("jobs were not delivered in time")
+		}
+	}
+	assert.Equal(t, []string{"b", "a"}, ids)
+}
@@ -0,0 +1,197 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// KernelVersion is the version of the running Linux kernel.
+type KernelVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v KernelVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether the kernel version is major.minor or newer.
+func (v KernelVersion) AtLeast(major, minor int) bool {
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+// parseKernelRelease parses a kernel release string such as "5.15.0-91-generic".
+func parseKernelRelease(release string) (v KernelVersion, err error) {
+	release = strings.SplitN(release, "-", 2)[0]
+	release = strings.SplitN(release, "+", 2)[0]
+	parts := strings.SplitN(release, ".", 3)
+	if len(parts) < 2 {
+		return v, fmt.Errorf("malformed kernel release %q", release)
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		if nums[i], err = strconv.Atoi(p); err != nil {
+			return v, fmt.Errorf("malformed kernel release %q: %w", release, err)
+		}
+	}
+	return KernelVersion{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+var kernelVersion struct {
+	once sync.Once
+	v    KernelVersion
+	err  error
+}
+
+// GetKernelVersion returns the version of the running kernel.
+// The version is read with uname(2) once and cached.
+func GetKernelVersion() (v KernelVersion, err error) {
+	kernelVersion.once.Do(func() {
+		var u unix.Utsname
+		if err := unix.Uname(&u); err != nil {
+			kernelVersion.err = fmt.Errorf("failed to read kernel version: %w", err)
+			return
+		}
+		kernelVersion.v, kernelVersion.err = parseKernelRelease(strings.TrimRight(string(u.Release[:]), "\x00"))
+	})
+	return kernelVersion.v, kernelVersion.err
+}
+
+// Feature identifies an optional real-time clock facility whose availability
+// depends on the kernel version and the device driver.
+type Feature int
+
+const (
+	// FeatureParam is RTC_PARAM_GET and RTC_PARAM_SET, available since Linux 5.16.
+	FeatureParam Feature = iota
+	// FeatureWakeAlarm is RTC_WKALM_RD and RTC_WKALM_SET.
+	FeatureWakeAlarm
+	// FeatureEpoch is RTC_EPOCH_READ and RTC_EPOCH_SET, only implemented by a few drivers.
+	FeatureEpoch
+	// FeatureVoltageLow is RTC_VL_READ and RTC_VL_CLR.
+	FeatureVoltageLow
+)
+
+func (f Feature) String() string {
+	switch f {
+	case FeatureParam:
+		return "param"
+	case FeatureWakeAlarm:
+		return "wake alarm"
+	case FeatureEpoch:
+		return "epoch"
+	case FeatureVoltageLow:
+		return "voltage low"
+	default:
+		return fmt.Sprintf("Feature(%d)", int(f))
+	}
+}
+
+// FeatureUnavailableError is returned by CheckFeature when a feature cannot
+// be used on a device.
+type FeatureUnavailableError struct {
+	Feature Feature
+	// Reason describes why the feature is unavailable.
+	Reason string
+	// Err is the error returned by the probe, if the feature was probed.
+	Err error
+}
+
+func (e *FeatureUnavailableError) Error() string {
+	return fmt.Sprintf("real-time clock %s feature unavailable: %s", e.Feature, e.Reason)
+}
+
+func (e *FeatureUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports that the error matches ErrNotSupported.
+func (e *FeatureUnavailableError) Is(target error) bool {
+	return target == ErrNotSupported
+}
+
+// featureCache holds probe results keyed by device number and feature so that
+// each device is probed at most once per process.
+var featureCache struct {
+	sync.Mutex
+	results map[featureKey]error
+}
+
+type featureKey struct {
+	rdev    uint64
+	feature Feature
+}
+
+// CheckFeature reports whether the feature is available on the real-time
+// clock. It returns nil if the feature can be used, or a
+// *FeatureUnavailableError explaining why it cannot, so that callers can
+// degrade gracefully. The kernel version is checked first, then the feature is
+// probed with a read-only ioctl. Results are cached per device.
+func (c *RTC) CheckFeature(f Feature) (err error) {
+	rdev, err := c.rdev()
+	if err != nil {
+		return err
+	}
+	key := featureKey{rdev: rdev, feature: f}
+
+	featureCache.Lock()
+	defer featureCache.Unlock()
+	if err, ok := featureCache.results[key]; ok {
+		return err
+	}
+
+	err = c.probeFeature(f)
+	var unavailable *FeatureUnavailableError
+	if err != nil && !errors.As(err, &unavailable) {
+		// Do not cache transient failures.
+		return err
+	}
+	if featureCache.results == nil {
+		featureCache.results = make(map[featureKey]error)
+	}
+	featureCache.results[key] = err
+	return err
+}
+
+func (c *RTC) probeFeature(f Feature) (err error) {
+	var req uintptr
+	var arg unsafe.Pointer
+	switch f {
+	case FeatureParam:
+		v, err := GetKernelVersion()
+		if err != nil {
+			return err
+		}
+		if !v.AtLeast(5, 16) {
+			return &FeatureUnavailableError{Feature: f, Reason: fmt.Sprintf("requires Linux 5.16, running %s", v)}
+		}
+		req, arg = rtcParamGet, unsafe.Pointer(&rtcParam{Param: rtcParamFeatures})
+	case FeatureWakeAlarm:
+		req, arg = unix.RTC_WKALM_RD, unsafe.Pointer(new(unix.RTCWkAlrm))
+	case FeatureEpoch:
+		req, arg = unix.RTC_EPOCH_READ, unsafe.Pointer(new(uint))
+	case FeatureVoltageLow:
+		req, arg = unix.RTC_VL_READ, unsafe.Pointer(new(uint32))
+	default:
+		return &FeatureUnavailableError{Feature: f, Reason: "unknown feature"}
+	}
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), req, uintptr(arg)); errno != 0 {
+		if isUnsupported(errno) {
+			return &FeatureUnavailableError{Feature: f, Reason: "not supported by the driver", Err: errno}
+		}
+		return fmt.Errorf("failed to probe real-time clock %s feature: %w", f, wrapErrno(errno))
+	}
+	return nil
+}
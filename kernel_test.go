@@ -0,0 +1,35 @@
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKernelRelease(t *testing.T) {
+	v, err := parseKernelRelease("5.15.0-91-generic")
+	require.NoError(t, err)
+	assert.Equal(t, KernelVersion{Major: 5, Minor: 15, Patch: 0}, v)
+
+	v, err = parseKernelRelease("6.1")
+	require.NoError(t, err)
+	assert.Equal(t, KernelVersion{Major: 6, Minor: 1}, v)
+
+	v, err = parseKernelRelease("4.19.94+")
+	require.NoError(t, err)
+	assert.Equal(t, KernelVersion{Major: 4, Minor: 19, Patch: 94}, v)
+
+	_, err = parseKernelRelease("linux")
+	assert.Error(t, err)
+
+	assert.True(t, KernelVersion{Major: 5, Minor: 16}.AtLeast(5, 16))
+	assert.True(t, KernelVersion{Major: 6, Minor: 0}.AtLeast(5, 16))
+	assert.False(t, KernelVersion{Major: 5, Minor: 15, Patch: 99}.AtLeast(5, 16))
+}
+
+func TestGetKernelVersion(t *testing.T) {
+	v, err := GetKernelVersion()
+	require.NoError(t, err)
+	assert.NotZero(t, v.Major)
+}
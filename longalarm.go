@@ -0,0 +1,132 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import "time"
+
+// maxAlarmHop is how far ahead intermediate alarms are armed for times beyond
+// the 24 hour reach of the standard alarm, leaving a margin for the time
+// spent re-arming.
+const maxAlarmHop = 23 * time.Hour
+
+// timerAlarm is the hardware alarm armed for a Timer.
+type timerAlarm struct {
+	// t is the time the alarm fires.
+	t time.Time
+	// hop is set while an intermediate alarm is armed because t is beyond the
+	// reach of the standard alarm.
+	hop bool
+	// wake is set if the wake alarm is armed.
+	wake bool
+}
+
+// alarmWait configures how waitTimerAlarm waits.
+type alarmWait struct {
+	missed MissedAlarmPolicy
+	// tick is called on every update interrupt, if not nil.
+	tick func()
+	// driftEvery is how often the alarm is adjusted for RTC drift, zero to
+	// disable. remaining returns the time left until the alarm should fire.
+	driftEvery time.Duration
+	remaining  func() time.Duration
+}
+
+// armLongAlarm arms the alarm to fire at t, however far ahead it is.
+// Within 24 hours the standard alarm is used. Further ahead the wake alarm is
+// used if the device supports it, since it carries a full date; otherwise an
+// intermediate standard alarm is armed as far toward t as possible, and hop is
+// set so that the caller re-arms once it fires.
+func (c *RTC) armLongAlarm(t time.Time) (hop bool, err error) {
+	now, err := c.GetTime()
+	if err != nil {
+		return false, err
+	}
+	if t.After(now.Add(maxAlarmHop)) {
+		if ok, err := c.SupportsWakeAlarm(); err != nil {
+			return false, err
+		} else if ok {
+			_, err := c.SetWakeAlarm(t)
+			return false, err
+		}
+		t, hop = now.Add(maxAlarmHop), true
+	}
+
+	if _, err := c.SetAlarm(t); err != nil {
+		return false, err
+	}
+	if err := c.SetAlarmInterrupt(true); err != nil {
+		return false, err
+	}
+	return hop, nil
+}
+
+// checkWakeReach returns a *RangeError if t is beyond the reach of the wake
+// alarm. Without RTC_WKALM_SET the wake alarm falls back to the sysfs
+// wakealarm attribute, which drivers with a time-of-day alarm silently fire
+// up to a day early, so it is limited to the next 24 hours.
+func (c *RTC) checkWakeReach(t time.Time) (err error) {
+	if ok, err := c.SupportsWakeAlarm(); err != nil || ok {
+		return err
+	}
+	now, err := c.GetTime()
+	if err != nil {
+		return err
+	}
+	if max := now.Add(24 * time.Hour); t.After(max) {
+		return &RangeError{Time: t, Min: now, Max: max}
+	}
+	return nil
+}
+
+// waitTimerAlarm waits with w until the alarm fires at a.t, re-arming
+// intermediate alarms along the way. It returns the interrupt that fired it.
+// Unless the missed alarm policy is MissedAlarmWait, it also checks the RTC
+// after the system resumes from suspend, and reports missed if the alarm time
+// passed without the interrupt.
+func (c *RTC) waitTimerAlarm(w *waiter, a *timerAlarm, aw alarmWait) (e Event, missed bool, err error) {
+	timeout := time.Duration(-1)
+	if aw.missed != MissedAlarmWait {
+		timeout = resumeCheckInterval
+	}
+	if aw.driftEvery > 0 && (timeout < 0 || aw.driftEvery < timeout) {
+		timeout = aw.driftEvery
+	}
+	last := readSuspendClock()
+	lastDrift := last
+	for {
+		e, ok, err := c.waitEventTimeout(w, timeout)
+		if err != nil {
+			return e, false, err
+		}
+		if !ok {
+			now := readSuspendClock()
+			resumed := now.suspendedSince(last)
+			last = now
+			if resumed && aw.missed != MissedAlarmWait {
+				if missed, err := c.checkMissedAlarm(a, aw.missed); missed || err != nil {
+					return Event{}, missed, err
+				}
+			}
+			if aw.driftEvery > 0 && now.boot-lastDrift.boot >= aw.driftEvery {
+				lastDrift = now
+				if err := c.compensateDrift(a, aw.remaining()); err != nil {
+					return Event{}, false, err
+				}
+			}
+			continue
+		}
+		if e.Update && aw.tick != nil {
+			aw.tick()
+		}
+		if !e.Alarm {
+			continue
+		}
+		if !a.hop {
+			return e, false, nil
+		}
+		if a.hop, err = c.armLongAlarm(a.t); err != nil {
+			return e, false, err
+		}
+	}
+}
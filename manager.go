@@ -0,0 +1,381 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Interrupt identifies one of the real-time clock's interrupt sources.
+type Interrupt int
+
+const (
+	InterruptAlarm Interrupt = iota
+	InterruptUpdate
+	InterruptPeriodic
+)
+
+func (i Interrupt) String() string {
+	switch i {
+	case InterruptAlarm:
+		return "alarm"
+	case InterruptUpdate:
+		return "update"
+	case InterruptPeriodic:
+		return "periodic"
+	default:
+		return fmt.Sprintf("Interrupt(%d)", int(i))
+	}
+}
+
+func (i Interrupt) valid() bool {
+	return i >= InterruptAlarm && i <= InterruptPeriodic
+}
+
+// Manager shares real-time clock devices between independent users within
+// a process. Each device is opened once, interrupt enables are reference
+// counted, and interrupt events are dispatched to every subscriber, so that
+// libraries in the same binary do not conflict over the device.
+type Manager struct {
+	mu      sync.Mutex
+	devices map[string]*sharedDevice
+}
+
+// DefaultManager is the Manager used by OpenShared.
+var DefaultManager = &Manager{}
+
+// OpenShared opens a shared handle to a real-time clock device using DefaultManager.
+func OpenShared(dev string) (*SharedRTC, error) {
+	return DefaultManager.Open(dev)
+}
+
+type sharedDevice struct {
+	m    *Manager
+	path string
+	rtc  *RTC
+	refs int
+
+	mu   sync.Mutex
+	irqs [3]int
+	subs map[chan Event]subscription
+	// armed is the kind of alarm most recently armed through the Manager.
+	armed AlarmKind
+
+	muxOnce sync.Once
+	mux     *timerMux
+
+	w    *waiter
+	wait sync.WaitGroup
+}
+
+// subscription selects the events delivered to a subscriber.
+type subscription struct {
+	alarmOnly bool
+	kind      AlarmKind
+}
+
+func (sub subscription) matches(e Event, armed AlarmKind) bool {
+	if !sub.alarmOnly {
+		return true
+	}
+	return e.Alarm && sub.kind == armed
+}
+
+// SharedRTC is a handle to a real-time clock device shared through a Manager.
+type SharedRTC struct {
+	d      *sharedDevice
+	mu     sync.Mutex
+	irqs   [3]int
+	closed bool
+}
+
+// Open returns a handle to the real-time clock device. The device is only
+// opened the first time; paths that resolve to the same device node, such as
+// /dev/rtc and /dev/rtc0, share the same device.
+func (m *Manager) Open(dev string) (*SharedRTC, error) {
+	path, err := filepath.EvalSymlinks(dev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rtc: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.devices[path]
+	if !ok {
+		c, err := NewRTC(path)
+		if err != nil {
+			return nil, err
+		}
+		w, err := newWaiter()
+		if err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+		d = &sharedDevice{
+			m:    m,
+			path: path,
+			rtc:  c,
+			subs: make(map[chan Event]subscription),
+			w:    w,
+		}
+		d.wait.Add(1)
+		go d.dispatch()
+
+		if m.devices == nil {
+			m.devices = make(map[string]*sharedDevice)
+		}
+		m.devices[path] = d
+	}
+	d.refs++
+
+	return &SharedRTC{d: d}, nil
+}
+
+// RTC returns the underlying device for operations that are not reference
+// counted, such as reading the time. It must not be closed directly, and
+// interrupt events must be received with Subscribe rather than read from it.
+func (s *SharedRTC) RTC() *RTC {
+	return s.d.rtc
+}
+
+// EnableInterrupt enables an interrupt on behalf of this handle.
+// The interrupt stays enabled on the device until every handle that enabled it
+// has disabled it or been closed.
+func (s *SharedRTC) EnableInterrupt(i Interrupt) (err error) {
+	if !i.valid() {
+		return fmt.Errorf("unknown real-time clock interrupt %s", i)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("failed to enable %s interrupt: %w", i, ErrClosed)
+	}
+	if err := s.d.setInterrupt(i, true); err != nil {
+		return err
+	}
+	s.irqs[i]++
+	return nil
+}
+
+// DisableInterrupt releases an interrupt previously enabled by this handle.
+func (s *SharedRTC) DisableInterrupt(i Interrupt) (err error) {
+	if !i.valid() {
+		return fmt.Errorf("unknown real-time clock interrupt %s", i)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.irqs[i] == 0 {
+		return nil
+	}
+	if err := s.d.setInterrupt(i, false); err != nil {
+		return err
+	}
+	s.irqs[i]--
+	return nil
+}
+
+// Subscribe returns a channel receiving every interrupt event read from the
+// device, and a function that cancels the subscription. If the subscriber
+// falls behind, events are dropped until it catches up. The channel is closed
+// when the subscription is cancelled or the device is closed.
+func (s *SharedRTC) Subscribe() (events <-chan Event, cancel func()) {
+	return s.subscribe(subscription{})
+}
+
+// SubscribeAlarm is like Subscribe but only receives the alarm events of the
+// given kind of alarm, as armed with SetStandardAlarm or SetWakeAlarm.
+// The hardware reports both kinds as the same interrupt, so events are
+// attributed to the kind of alarm that was armed most recently through the
+// Manager. This is a guess: an alarm armed on the device outside the Manager,
+// for example with the RTC returned by RTC or by another process, is not
+// seen, and its events are attributed to the kind last armed through the
+// Manager, or to AlarmStandard if none was.
+func (s *SharedRTC) SubscribeAlarm(kind AlarmKind) (events <-chan Event, cancel func()) {
+	return s.subscribe(subscription{alarmOnly: true, kind: kind})
+}
+
+func (s *SharedRTC) subscribe(sub subscription) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, 8)
+	s.d.mu.Lock()
+	if s.d.subs == nil {
+		close(ch)
+	} else {
+		s.d.subs[ch] = sub
+	}
+	s.d.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.d.mu.Lock()
+			defer s.d.mu.Unlock()
+			if _, ok := s.d.subs[ch]; ok {
+				delete(s.d.subs, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// SetStandardAlarm programs the standard alarm and enables the alarm
+// interrupt on behalf of this handle. It returns the effective alarm time.
+// Alarm events are delivered to SubscribeAlarm(AlarmStandard).
+func (s *SharedRTC) SetStandardAlarm(t time.Time) (effective time.Time, err error) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return time.Time{}, fmt.Errorf("failed to set alarm: %w", ErrClosed)
+	}
+	s.d.mu.Lock()
+	effective, err = s.d.rtc.SetAlarm(t)
+	if err == nil {
+		s.d.armed = AlarmStandard
+	}
+	s.d.mu.Unlock()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	s.mu.Lock()
+	held := s.irqs[InterruptAlarm] > 0
+	s.mu.Unlock()
+	if !held {
+		if err := s.EnableInterrupt(InterruptAlarm); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return effective, nil
+}
+
+// SetWakeAlarm programs and enables the wake alarm. It returns the effective
+// alarm time. Alarm events are delivered to SubscribeAlarm(AlarmWake).
+func (s *SharedRTC) SetWakeAlarm(t time.Time) (effective time.Time, err error) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return time.Time{}, fmt.Errorf("failed to set wake alarm: %w", ErrClosed)
+	}
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	effective, err = s.d.rtc.SetWakeAlarm(t)
+	if err != nil {
+		return time.Time{}, err
+	}
+	s.d.armed = AlarmWake
+	return effective, nil
+}
+
+// Close releases the handle and any interrupts it still holds. The device is
+// closed when its last handle is closed.
+func (s *SharedRTC) Close() (err error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.d.mu.Lock()
+	mux := s.d.mux
+	s.d.mu.Unlock()
+	if mux != nil {
+		mux.removeOwner(s)
+	}
+	for i, n := range s.irqs {
+		for ; n > 0; n-- {
+			_ = s.d.setInterrupt(Interrupt(i), false)
+		}
+		s.irqs[i] = 0
+	}
+	s.mu.Unlock()
+
+	return s.d.release()
+}
+
+// setInterrupt adjusts the reference count of an interrupt, issuing the ioctl
+// when it changes between zero and non-zero.
+func (d *sharedDevice) setInterrupt(i Interrupt, enable bool) (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if enable {
+		if d.irqs[i] == 0 {
+			if err := d.rtc.setInterrupt(i, true); err != nil {
+				return err
+			}
+		}
+		d.irqs[i]++
+		return nil
+	}
+
+	if d.irqs[i] == 1 {
+		if err := d.rtc.setInterrupt(i, false); err != nil {
+			return err
+		}
+	}
+	d.irqs[i]--
+	return nil
+}
+
+// release drops a reference to the device, closing it with the last one.
+func (d *sharedDevice) release() (err error) {
+	d.m.mu.Lock()
+	d.refs--
+	last := d.refs == 0
+	if last {
+		delete(d.m.devices, d.path)
+	}
+	d.m.mu.Unlock()
+
+	if !last {
+		return nil
+	}
+
+	d.w.wake()
+	d.wait.Wait()
+	d.w.close()
+	return d.rtc.Close()
+}
+
+// dispatch reads interrupt events from the device and fans them out to the
+// subscribers until the device is released.
+func (d *sharedDevice) dispatch() {
+	defer d.wait.Done()
+	defer func() {
+		d.mu.Lock()
+		for ch := range d.subs {
+			close(ch)
+		}
+		d.subs = nil
+		d.mu.Unlock()
+	}()
+
+	for {
+		readable, err := d.w.wait(d.rtc.fd(), -1)
+		if err != nil || !readable {
+			return
+		}
+		e, err := d.rtc.ReadEvent()
+		if err != nil {
+			return
+		}
+
+		d.mu.Lock()
+		for ch, sub := range d.subs {
+			if !sub.matches(e, d.armed) {
+				continue
+			}
+			select {
+			case ch <- e:
+			default:
+			}
+		}
+		d.mu.Unlock()
+	}
+}
@@ -0,0 +1,64 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	m := &Manager{}
+
+	a, err := m.Open("/dev/rtc")
+	require.NoError(t, err)
+	defer a.Close()
+
+	b, err := m.Open("/dev/rtc")
+	require.NoError(t, err)
+	defer b.Close()
+
+	// Both handles share the same device
+	assert.Same(t, a.RTC(), b.RTC())
+
+	events, cancel := b.Subscribe()
+	defer cancel()
+
+	require.NoError(t, a.EnableInterrupt(InterruptUpdate))
+	require.NoError(t, b.EnableInterrupt(InterruptUpdate))
+
+	// Releasing one handle's interrupt must not disable it for the other
+	require.NoError(t, a.DisableInterrupt(InterruptUpdate))
+
+	select {
+	case e := <-events:
+		assert.True(t, e.Update)
+	case <-time.After(2 * time.Second):
+		t.Error("update interrupt did not trigger in time")
+	}
+}
+
+func TestSubscriptionMatches(t *testing.T) {
+	all := subscription{}
+	wake := subscription{alarmOnly: true, kind: AlarmWake}
+
+	update := Event{Update: true, Count: 1}
+	alarm := Event{Alarm: true, Count: 1}
+
+	assert.True(t, all.matches(update, AlarmStandard))
+	assert.True(t, all.matches(alarm, AlarmStandard))
+	assert.False(t, wake.matches(update, AlarmWake))
+	assert.False(t, wake.matches(alarm, AlarmStandard))
+	assert.True(t, wake.matches(alarm, AlarmWake))
+}
+
+func TestSharedRTCClosedAlarm(t *testing.T) {
+	s := &SharedRTC{d: &sharedDevice{}, closed: true}
+	_, err := s.SetStandardAlarm(time.Now().Add(time.Minute))
+	assert.True(t, errors.Is(err, ErrClosed))
+	_, err = s.SetWakeAlarm(time.Now().Add(time.Minute))
+	assert.True(t, errors.Is(err, ErrClosed))
+	assert.True(t, errors.Is(s.EnableInterrupt(InterruptUpdate), ErrClosed))
+}
@@ -0,0 +1,118 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WriteMetrics writes metrics describing the specified real-time clock device
+// to w in the Prometheus text exposition format. The wake alarm series are
+// left out for devices that do not support reading the wake alarm.
+func WriteMetrics(w io.Writer, dev string) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	t, err := c.GetTime()
+	if err != nil {
+		return err
+	}
+	offset := t.Sub(time.Now())
+	freq, err := c.GetFrequency()
+	if err != nil {
+		return err
+	}
+	var wake *WakeAlarm
+	alarm, err := c.WakeAlarm()
+	switch {
+	case err == nil:
+		wake = &alarm
+	case !isUnsupported(err):
+		return err
+	}
+
+	_, err = io.WriteString(w, formatMetrics(dev, t, offset, freq, wake))
+	return err
+}
+
+// formatMetrics formats the metrics of the device dev, leaving out the wake
+// alarm series if wake is nil.
+func formatMetrics(dev string, t time.Time, offset time.Duration, freq uint, wake *WakeAlarm) string {
+	label := fmt.Sprintf("{device=%q}", dev)
+	var b strings.Builder
+	writeMetric(&b, "rtc_time_seconds", "gauge", "Time reported by the real-time clock, in seconds since the Unix epoch.", label, float64(t.Unix()))
+	writeMetric(&b, "rtc_offset_seconds", "gauge", "Offset of the real-time clock relative to the system clock, with one second resolution.", label, offset.Seconds())
+	writeMetric(&b, "rtc_periodic_frequency_hertz", "gauge", "Periodic interrupt frequency of the real-time clock.", label, float64(freq))
+	if wake != nil {
+		wakeEnabled := 0.0
+		if wake.Enabled {
+			wakeEnabled = 1
+		}
+		writeMetric(&b, "rtc_wake_alarm_enabled", "gauge", "Whether the real-time clock's wake alarm is enabled.", label, wakeEnabled)
+		writeMetric(&b, "rtc_wake_alarm_time_seconds", "gauge", "Time of the real-time clock's wake alarm, in seconds since the Unix epoch.", label, float64(wake.Time.Unix()))
+	}
+	return b.String()
+}
+
+func writeMetric(b *strings.Builder, name, typ, help, labels string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+	fmt.Fprintf(b, "%s%s %g\n", name, labels, value)
+}
+
+// WriteTextfile writes the metrics of the specified real-time clock device to
+// the file name in dir, in the format read by the node_exporter textfile
+// collector. The file is written to a temporary file and renamed so that the
+// collector never reads a partial file. The name should end in ".prom".
+func WriteTextfile(dir, name, dev string) (err error) {
+	f, err := os.CreateTemp(dir, "."+name+".")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := WriteMetrics(f, dev); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// RunTextfileCollector calls WriteTextfile every interval until the context
+// is done, for minimal systems that already run node_exporter and do not want
+// another listener. Errors are passed to onError if it is not nil, and do not
+// stop the collector.
+func RunTextfileCollector(ctx context.Context, dir, name, dev string, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := WriteTextfile(dir, name, dev); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
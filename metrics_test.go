@@ -0,0 +1,45 @@
+package rtc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMetric(t *testing.T) {
+	var b strings.Builder
+	writeMetric(&b, "rtc_test", "gauge", "Test metric.", `{device="/dev/rtc"}`, 1.5)
+	assert.Equal(t, "# HELP rtc_test Test metric.\n# TYPE rtc_test gauge\nrtc_test{device=\"/dev/rtc\"} 1.5\n", b.String())
+}
+
+func TestFormatMetrics(t *testing.T) {
+	tm := time.Unix(1900000000, 0)
+	wake := &WakeAlarm{Enabled: true, Time: tm.Add(time.Hour)}
+	metrics := formatMetrics("/dev/rtc", tm, 0, 64, wake)
+	assert.Contains(t, metrics, "rtc_periodic_frequency_hertz{device=\"/dev/rtc\"} 64\n")
+	assert.Contains(t, metrics, "rtc_wake_alarm_enabled{device=\"/dev/rtc\"} 1\n")
+
+	// Devices without a readable wake alarm still export the other series
+	metrics = formatMetrics("/dev/rtc", tm, 0, 64, nil)
+	assert.Contains(t, metrics, "rtc_time_seconds{device=\"/dev/rtc\"}")
+	assert.NotContains(t, metrics, "rtc_wake_alarm")
+}
+
+func TestWriteTextfile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteTextfile(dir, "rtc.prom", "/dev/rtc"))
+
+	b, err := os.ReadFile(filepath.Join(dir, "rtc.prom"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "rtc_time_seconds{device=\"/dev/rtc\"}")
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
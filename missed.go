@@ -0,0 +1,62 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MissedAlarmPolicy selects how a Timer handles an alarm whose time passed
+// while the system was suspended without the alarm waking it. Most drivers
+// then never deliver the interrupt, so the Timer would wait forever.
+type MissedAlarmPolicy int
+
+const (
+	// MissedAlarmFire makes the Timer fire on resume, with Alarm.Missed set.
+	MissedAlarmFire MissedAlarmPolicy = iota
+	// MissedAlarmFail ends the Timer on resume with an error wrapping
+	// ErrAlarmMissed on its Err channel.
+	MissedAlarmFail
+	// MissedAlarmWait keeps waiting for the alarm interrupt.
+	MissedAlarmWait
+)
+
+// ErrAlarmMissed is reported when a Timer's alarm time passed while the system
+// was suspended and the missed alarm policy is MissedAlarmFail.
+var ErrAlarmMissed = errors.New("alarm time passed while the system was suspended")
+
+// resumeCheckInterval is how often a waiting Timer checks whether the system
+// has resumed from suspend.
+const resumeCheckInterval = 5 * time.Second
+
+// WithMissedAlarmPolicy sets how the Timer handles an alarm missed while the
+// system was suspended. The default is MissedAlarmFire.
+func WithMissedAlarmPolicy(p MissedAlarmPolicy) TimerOption {
+	return func(o *timerOptions) {
+		o.missedAlarm = p
+	}
+}
+
+// checkMissedAlarm is called after the system resumed while a Timer waited
+// for the alarm armed with a. It reports whether the alarm time has passed
+// according to the RTC, and otherwise re-arms an intermediate alarm that may
+// have passed.
+func (c *RTC) checkMissedAlarm(a *timerAlarm, p MissedAlarmPolicy) (missed bool, err error) {
+	now, err := c.GetTime()
+	if err != nil {
+		return false, err
+	}
+	if alarmInPast(a.t, now) {
+		if p == MissedAlarmFail {
+			return true, fmt.Errorf("%w: alarm at %v, resumed at %v", ErrAlarmMissed, a.t, now)
+		}
+		return true, nil
+	}
+	if a.hop {
+		a.hop, err = c.armLongAlarm(a.t)
+	}
+	return false, err
+}
@@ -0,0 +1,73 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// nvramPath returns the path of the battery-backed NVRAM of the device: the
+// nvmem device registered by its driver, or the legacy nvram attribute.
+func (c *RTC) nvramPath() (path string, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return "", err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "device", "*", "nvmem"))
+	if err != nil {
+		return "", err
+	}
+	paths = append(paths, filepath.Join(dir, "nvram"))
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find real-time clock NVRAM: %w", ErrNotSupported)
+}
+
+// ReadNVRAM reads n bytes at offset from the real-time clock's battery-backed
+// NVRAM, so that applications can keep small persistent data alongside the
+// clock. Fewer bytes are returned if the NVRAM ends before offset+n. For
+// devices without NVRAM the returned error wraps ErrNotSupported.
+func (c *RTC) ReadNVRAM(offset int64, n int) (data []byte, err error) {
+	path, err := c.nvramPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open real-time clock NVRAM: %w", wrapErrno(err))
+	}
+	defer f.Close()
+
+	data = make([]byte, n)
+	read, err := f.ReadAt(data, offset)
+	if err != nil && read == 0 {
+		return nil, fmt.Errorf("failed to read real-time clock NVRAM: %w", err)
+	}
+	return data[:read], nil
+}
+
+// WriteNVRAM writes data at offset to the real-time clock's battery-backed
+// NVRAM, which usually requires root privileges. For devices without NVRAM the
+// returned error wraps ErrNotSupported.
+func (c *RTC) WriteNVRAM(offset int64, data []byte) (err error) {
+	path, err := c.nvramPath()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open real-time clock NVRAM: %w", wrapErrno(err))
+	}
+	defer f.Close()
+
+	if _, err := f.WriteAt(data, offset); err != nil {
+		return fmt.Errorf("failed to write real-time clock NVRAM: %w", err)
+	}
+	return f.Close()
+}
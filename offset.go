@@ -0,0 +1,89 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// clockNow reads the given clock as a duration since its epoch.
+func clockNow(clock int32) (t time.Duration, err error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(clock, &ts); err != nil {
+		return 0, fmt.Errorf("failed to read clock %d: %w", clock, err)
+	}
+	return time.Duration(ts.Nano()), nil
+}
+
+// MeasureOffset measures the offset of the real-time clock relative to the
+// system clock with sub-second resolution.
+// It observes the given number of update interrupt edges, each marking the
+// start of an RTC second, and timestamps them with CLOCK_MONOTONIC_RAW, which
+// is not affected by NTP slewing. The edge with the least delivery latency
+// fixes the phase of the RTC's seconds, which is related to the system clock
+// through a single paired reading at the end, so adjustments of the system
+// clock during the measurement do not skew the result.
+// A positive offset means the RTC is ahead of the system clock.
+func (c *RTC) MeasureOffset(edges int) (offset time.Duration, err error) {
+	if edges <= 0 {
+		return 0, errors.New("non-positive edge count for MeasureOffset")
+	}
+
+	if err := c.SetUpdateInterrupt(true); err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = c.SetUpdateInterrupt(false)
+	}()
+
+	var first time.Time
+	var phase time.Duration
+	for i := 0; i < edges; {
+		e, err := c.ReadEvent()
+		if err != nil {
+			return 0, err
+		}
+		raw, err := clockNow(unix.CLOCK_MONOTONIC_RAW)
+		if err != nil {
+			return 0, err
+		}
+		if !e.Update {
+			continue
+		}
+		if i == 0 {
+			if first, err = c.GetTime(); err != nil {
+				return 0, err
+			}
+		}
+
+		// Project the edge back onto the first edge; the earliest projection
+		// has the least latency.
+		p := raw - time.Duration(i)*time.Second
+		if i == 0 || p < phase {
+			phase = p
+		}
+		i++
+	}
+
+	raw1, err := clockNow(unix.CLOCK_MONOTONIC_RAW)
+	if err != nil {
+		return 0, err
+	}
+	sys, err := clockNow(unix.CLOCK_REALTIME)
+	if err != nil {
+		return 0, err
+	}
+	raw2, err := clockNow(unix.CLOCK_MONOTONIC_RAW)
+	if err != nil {
+		return 0, err
+	}
+
+	// System time at which the first edge occurred
+	edge := time.Unix(0, int64(sys-((raw1+raw2)/2-phase)))
+	return first.Sub(edge), nil
+}
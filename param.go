@@ -0,0 +1,220 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// RTC_PARAM_GET and RTC_PARAM_SET were introduced in Linux 5.16 and are not
+// defined by golang.org/x/sys/unix.
+const (
+	rtcParamGet = 0x40187013
+	rtcParamSet = 0x40187014
+)
+
+// Parameters accessed with RTC_PARAM_GET and RTC_PARAM_SET.
+const (
+	rtcParamFeatures         = 0
+	rtcParamCorrection       = 1
+	rtcParamBackupSwitchMode = 2
+)
+
+// rtcParam mirrors the kernel's struct rtc_param.
+type rtcParam struct {
+	Param uint64
+	Value uint64
+	Index uint32
+	_     uint32
+}
+
+// paramGet reads a parameter using RTC_PARAM_GET.
+func (c *RTC) paramGet(param uint64) (value uint64, err error) {
+	p := &rtcParam{Param: param}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), rtcParamGet, uintptr(unsafe.Pointer(p))); errno != 0 {
+		return 0, fmt.Errorf("failed to read real-time clock parameter %d: %w", param, wrapErrno(errno))
+	}
+	return p.Value, nil
+}
+
+// paramSet writes a parameter using RTC_PARAM_SET.
+func (c *RTC) paramSet(param uint64, value uint64) (err error) {
+	p := &rtcParam{Param: param, Value: value}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), rtcParamSet, uintptr(unsafe.Pointer(p))); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock parameter %d: %w", param, wrapErrno(errno))
+	}
+	return nil
+}
+
+// DeviceFeatures is the set of capabilities of a real-time clock device, as
+// reported by the RTC_PARAM_FEATURES parameter.
+type DeviceFeatures uint64
+
+// Device feature bits.
+const (
+	// DeviceFeatureAlarm is set when the device has an alarm.
+	DeviceFeatureAlarm DeviceFeatures = 1 << iota
+	// DeviceFeatureAlarmResMinute is set when the alarm ignores seconds.
+	DeviceFeatureAlarmResMinute
+	// DeviceFeatureNeedWeekDay is set when the device needs the day of the week.
+	DeviceFeatureNeedWeekDay
+	// DeviceFeatureAlarmRes2s is set when the alarm has a two second resolution.
+	DeviceFeatureAlarmRes2s
+	// DeviceFeatureUpdateInterrupt is set when the device supports update
+	// interrupts.
+	DeviceFeatureUpdateInterrupt
+	// DeviceFeatureCorrection is set when the device supports frequency
+	// correction.
+	DeviceFeatureCorrection
+	// DeviceFeatureBackupSwitchMode is set when the backup switch mode can be
+	// configured.
+	DeviceFeatureBackupSwitchMode
+	// DeviceFeatureAlarmWakeupOnly is set when the alarm can only wake the
+	// system and does not raise an interrupt.
+	DeviceFeatureAlarmWakeupOnly
+)
+
+var deviceFeatureNames = []string{
+	"alarm",
+	"alarm minute resolution",
+	"need week day",
+	"alarm 2s resolution",
+	"update interrupt",
+	"correction",
+	"backup switch mode",
+	"alarm wakeup only",
+}
+
+// Has reports whether all of the given features are set.
+func (f DeviceFeatures) Has(features DeviceFeatures) bool {
+	return f&features == features
+}
+
+func (f DeviceFeatures) String() string {
+	var names []string
+	for i, name := range deviceFeatureNames {
+		if f&(1<<uint(i)) != 0 {
+			names = append(names, name)
+			f &^= 1 << uint(i)
+		}
+	}
+	if f != 0 {
+		names = append(names, fmt.Sprintf("0x%x", uint64(f)))
+	}
+	return strings.Join(names, "|")
+}
+
+// GetParamFeatures returns the device features reported by the
+// RTC_PARAM_FEATURES parameter, available since Linux 5.16.
+func (c *RTC) GetParamFeatures() (features DeviceFeatures, err error) {
+	if err := c.CheckFeature(FeatureParam); err != nil {
+		return 0, err
+	}
+	v, err := c.paramGet(rtcParamFeatures)
+	if err != nil {
+		return 0, err
+	}
+	return DeviceFeatures(v), nil
+}
+
+// requireParamFeature returns an error wrapping ErrNotSupported unless the
+// device reports the given features. The kernel rejects parameters of
+// unsupported features with EINVAL, which is indistinguishable from an
+// invalid value.
+func (c *RTC) requireParamFeature(features DeviceFeatures) (err error) {
+	f, err := c.GetParamFeatures()
+	if err != nil {
+		return err
+	}
+	if !f.Has(features) {
+		return fmt.Errorf("real-time clock does not support %v: %w", features, ErrNotSupported)
+	}
+	return nil
+}
+
+// GetCorrection returns the real-time clock's frequency correction in parts
+// per billion, read from the RTC_PARAM_CORRECTION parameter. If the device does
+// not support it, the returned error wraps ErrNotSupported.
+func (c *RTC) GetCorrection() (ppb int64, err error) {
+	if err := c.requireParamFeature(DeviceFeatureCorrection); err != nil {
+		return 0, err
+	}
+	v, err := c.paramGet(rtcParamCorrection)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v), nil
+}
+
+// SetCorrection sets the real-time clock's frequency correction in parts per
+// billion with the RTC_PARAM_CORRECTION parameter. A positive correction slows
+// the clock down. If the device does not support it, the returned error wraps
+// ErrNotSupported.
+func (c *RTC) SetCorrection(ppb int64) (err error) {
+	if err := c.requireParamFeature(DeviceFeatureCorrection); err != nil {
+		return err
+	}
+	return c.paramSet(rtcParamCorrection, uint64(ppb))
+}
+
+// BackupSwitchMode selects how a real-time clock switches to its backup power
+// supply.
+type BackupSwitchMode uint64
+
+const (
+	// BackupSwitchDisabled never switches to the backup supply.
+	BackupSwitchDisabled BackupSwitchMode = iota
+	// BackupSwitchDirect switches when the main supply drops below the
+	// backup supply.
+	BackupSwitchDirect
+	// BackupSwitchLevel switches when the main supply drops below a fixed
+	// threshold.
+	BackupSwitchLevel
+	// BackupSwitchStandby keeps the backup supply disconnected until the main
+	// supply is restored, for shipping devices without draining the battery.
+	BackupSwitchStandby
+)
+
+func (m BackupSwitchMode) String() string {
+	switch m {
+	case BackupSwitchDisabled:
+		return "disabled"
+	case BackupSwitchDirect:
+		return "direct"
+	case BackupSwitchLevel:
+		return "level"
+	case BackupSwitchStandby:
+		return "standby"
+	default:
+		return fmt.Sprintf("BackupSwitchMode(%d)", uint64(m))
+	}
+}
+
+// GetBackupSwitchMode returns the real-time clock's backup switch mode, read
+// from the RTC_PARAM_BACKUP_SWITCH_MODE parameter. If the device does not
+// support it, the returned error wraps ErrNotSupported.
+func (c *RTC) GetBackupSwitchMode() (mode BackupSwitchMode, err error) {
+	if err := c.requireParamFeature(DeviceFeatureBackupSwitchMode); err != nil {
+		return 0, err
+	}
+	v, err := c.paramGet(rtcParamBackupSwitchMode)
+	if err != nil {
+		return 0, err
+	}
+	return BackupSwitchMode(v), nil
+}
+
+// SetBackupSwitchMode sets the real-time clock's backup switch mode with the
+// RTC_PARAM_BACKUP_SWITCH_MODE parameter, as supported by chips such as the
+// PCF2127 and RV-3028. If the device does not support it, the returned error
+// wraps ErrNotSupported.
+func (c *RTC) SetBackupSwitchMode(mode BackupSwitchMode) (err error) {
+	if err := c.requireParamFeature(DeviceFeatureBackupSwitchMode); err != nil {
+		return err
+	}
+	return c.paramSet(rtcParamBackupSwitchMode, uint64(mode))
+}
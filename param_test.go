@@ -0,0 +1,22 @@
+package rtc
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRtcParamSize(t *testing.T) {
+	// RTC_PARAM_GET encodes the size of struct rtc_param
+	assert.Equal(t, uintptr(0x18), unsafe.Sizeof(rtcParam{}))
+}
+
+func TestDeviceFeatures(t *testing.T) {
+	f := DeviceFeatureAlarm | DeviceFeatureUpdateInterrupt
+	assert.True(t, f.Has(DeviceFeatureAlarm))
+	assert.False(t, f.Has(DeviceFeatureAlarm|DeviceFeatureCorrection))
+	assert.Equal(t, "alarm|update interrupt", f.String())
+	assert.Equal(t, "alarm wakeup only|0x100", (DeviceFeatureAlarmWakeupOnly | 0x100).String())
+	assert.Equal(t, "standby", BackupSwitchStandby.String())
+}
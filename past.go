@@ -0,0 +1,70 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PastAlarmPolicy selects how alarm times that are not in the future are
+// handled. Depending on the driver such alarms never fire or fire
+// unpredictably.
+type PastAlarmPolicy int
+
+const (
+	// PastAlarmAllow programs past alarm times unchanged.
+	PastAlarmAllow PastAlarmPolicy = iota
+	// PastAlarmError rejects past alarm times with an error wrapping
+	// ErrAlarmInPast.
+	PastAlarmError
+	// PastAlarmFireNow makes a Timer fire immediately. SetAlarm and
+	// SetWakeAlarm treat it like PastAlarmClamp, since the soonest the
+	// hardware can fire is the next second.
+	PastAlarmFireNow
+	// PastAlarmClamp moves past alarm times to one second after the current
+	// RTC time.
+	PastAlarmClamp
+)
+
+// ErrAlarmInPast is returned when an alarm time is not in the future and the
+// past alarm policy is PastAlarmError.
+var ErrAlarmInPast = errors.New("alarm time is in the past")
+
+// SetPastAlarmPolicy sets how SetAlarm and SetWakeAlarm handle alarm times
+// that are not after the current RTC time. The default is PastAlarmAllow.
+func (c *RTC) SetPastAlarmPolicy(p PastAlarmPolicy) {
+	c.pastAlarm = p
+}
+
+// checkPastAlarm applies the past alarm policy to t.
+func (c *RTC) checkPastAlarm(t time.Time) (checked time.Time, err error) {
+	if c.pastAlarm == PastAlarmAllow {
+		return t, nil
+	}
+	now, err := c.GetTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return applyPastAlarmPolicy(t, now, c.pastAlarm)
+}
+
+// alarmInPast reports whether an alarm at t would not fire after the RTC time
+// now.
+func alarmInPast(t time.Time, now time.Time) bool {
+	return !timeRtc{Time: t}.truncated().After(now)
+}
+
+func applyPastAlarmPolicy(t time.Time, now time.Time, p PastAlarmPolicy) (checked time.Time, err error) {
+	if p == PastAlarmAllow || !alarmInPast(t, now) {
+		return t, nil
+	}
+	switch p {
+	case PastAlarmError:
+		return time.Time{}, fmt.Errorf("%w: %v is not after %v", ErrAlarmInPast, t, now)
+	default:
+		return now.Add(time.Second), nil
+	}
+}
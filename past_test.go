@@ -0,0 +1,38 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPastAlarmPolicy(t *testing.T) {
+	now := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	for _, p := range []PastAlarmPolicy{PastAlarmAllow, PastAlarmError, PastAlarmFireNow, PastAlarmClamp} {
+		checked, err := applyPastAlarmPolicy(future, now, p)
+		require.NoError(t, err)
+		assert.True(t, future.Equal(checked))
+	}
+
+	checked, err := applyPastAlarmPolicy(past, now, PastAlarmAllow)
+	require.NoError(t, err)
+	assert.True(t, past.Equal(checked))
+
+	_, err = applyPastAlarmPolicy(past, now, PastAlarmError)
+	assert.True(t, errors.Is(err, ErrAlarmInPast))
+
+	// The current second is too late for the hardware
+	checked, err = applyPastAlarmPolicy(now.Add(500*time.Millisecond), now, PastAlarmClamp)
+	require.NoError(t, err)
+	assert.True(t, now.Add(time.Second).Equal(checked))
+
+	checked, err = applyPastAlarmPolicy(past, now, PastAlarmFireNow)
+	require.NoError(t, err)
+	assert.True(t, now.Add(time.Second).Equal(checked))
+}
@@ -0,0 +1,68 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// PLLInfo is the state of a real-time clock's PLL frequency correction, as
+// read with RTC_PLL_GET. Only a few drivers, such as those of the m41t80
+// family, implement it.
+type PLLInfo struct {
+	// Ctrl holds driver specific control bits.
+	Ctrl int
+	// Value is the current correction.
+	Value int
+	// Max is the largest supported correction.
+	Max int
+	// Min is the smallest supported correction.
+	Min int
+	// PosMult is the factor for a positive correction.
+	PosMult int
+	// NegMult is the factor for a negative correction.
+	NegMult int
+	// Clock is the base PLL frequency in Hz.
+	Clock int64
+}
+
+// GetPLL returns the real-time clock's PLL correction state, read with
+// RTC_PLL_GET.
+func (c *RTC) GetPLL() (info PLLInfo, err error) {
+	p := new(unix.RTCPLLInfo)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_PLL_GET, uintptr(unsafe.Pointer(p))); errno != 0 {
+		return PLLInfo{}, fmt.Errorf("failed to read real-time clock PLL: %w", wrapErrno(errno))
+	}
+	return PLLInfo{
+		Ctrl:    int(p.Ctrl),
+		Value:   int(p.Value),
+		Max:     int(p.Max),
+		Min:     int(p.Min),
+		PosMult: int(p.Posmult),
+		NegMult: int(p.Negmult),
+		Clock:   int64(p.Clock),
+	}, nil
+}
+
+// SetPLL sets the real-time clock's PLL correction with RTC_PLL_SET.
+// Drivers typically only apply Value, which must be within Min and Max.
+func (c *RTC) SetPLL(info PLLInfo) (err error) {
+	p := &unix.RTCPLLInfo{
+		Ctrl:    int32(info.Ctrl),
+		Value:   int32(info.Value),
+		Max:     int32(info.Max),
+		Min:     int32(info.Min),
+		Posmult: int32(info.PosMult),
+		Negmult: int32(info.NegMult),
+		Clock:   clong(info.Clock),
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_PLL_SET, uintptr(unsafe.Pointer(p))); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock PLL: %w", wrapErrno(errno))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// waiter waits for a file descriptor to become readable and can be woken up
+// from another goroutine, which a blocking read on the RTC cannot.
+type waiter struct {
+	efd int
+}
+
+func newWaiter() (*waiter, error) {
+	efd, err := unix.Eventfd(0, unix.EFD_CLOEXEC|unix.EFD_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eventfd: %w", err)
+	}
+	return &waiter{efd: efd}, nil
+}
+
+// newContextWaiter returns a waiter that is woken when ctx is done. The
+// returned release function stops watching ctx and closes the waiter.
+func newContextWaiter(ctx context.Context) (w *waiter, release func(), err error) {
+	w, err = newWaiter()
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+			w.wake()
+		case <-stop:
+		}
+	}()
+	return w, func() {
+		close(stop)
+		<-done
+		w.close()
+	}, nil
+}
+
+// wake causes pending and future calls to wait to return false.
+func (w *waiter) wake() {
+	buf := []byte{1, 0, 0, 0, 0, 0, 0, 0}
+	_, _ = unix.Write(w.efd, buf)
+}
+
+// woken reports whether the waiter has been woken.
+func (w *waiter) woken() bool {
+	fds := []unix.PollFd{{Fd: int32(w.efd), Events: unix.POLLIN}}
+	n, err := unix.Poll(fds, 0)
+	return err == nil && n > 0
+}
+
+// wait blocks until fd is readable, the waiter is woken, or the timeout
+// expires. A negative timeout waits indefinitely. It reports whether fd is
+// readable.
+func (w *waiter) wait(fd int, timeout time.Duration) (readable bool, err error) {
+	return w.waitFor(fd, unix.POLLIN, timeout)
+}
+
+// waitFor is like wait, but waits for the given poll events on fd, and
+// reports whether one of them occurred.
+func (w *waiter) waitFor(fd int, events int16, timeout time.Duration) (ready bool, err error) {
+	ms := -1
+	if timeout >= 0 {
+		ms = int((timeout + time.Millisecond - 1) / time.Millisecond)
+	}
+	fds := []unix.PollFd{
+		{Fd: int32(fd), Events: events},
+		{Fd: int32(w.efd), Events: unix.POLLIN},
+	}
+	for {
+		_, err := unix.Poll(fds, ms)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to poll real-time clock: %w", err)
+		}
+		if fds[1].Revents != 0 {
+			return false, nil
+		}
+		if fds[0].Revents&(unix.POLLERR|unix.POLLHUP|unix.POLLNVAL) != 0 {
+			return false, fmt.Errorf("failed to poll real-time clock: revents 0x%x", fds[0].Revents)
+		}
+		return fds[0].Revents&events != 0, nil
+	}
+}
+
+// pollReadable blocks until fd is readable or the timeout expires. A negative
+// timeout waits indefinitely. It reports whether fd is readable.
+func pollReadable(fd int, timeout time.Duration) (readable bool, err error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		ms := -1
+		if timeout >= 0 {
+			remaining := time.Until(deadline)
+			if remaining < 0 {
+				remaining = 0
+			}
+			ms = int((remaining + time.Millisecond - 1) / time.Millisecond)
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		_, err := unix.Poll(fds, ms)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to poll real-time clock: %w", err)
+		}
+		if fds[0].Revents&(unix.POLLERR|unix.POLLHUP|unix.POLLNVAL) != 0 {
+			return false, fmt.Errorf("failed to poll real-time clock: revents 0x%x", fds[0].Revents)
+		}
+		return fds[0].Revents&unix.POLLIN != 0, nil
+	}
+}
+
+func (w *waiter) close() {
+	_ = unix.Close(w.efd)
+}
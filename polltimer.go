@@ -0,0 +1,141 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// PollTimer is a timer for programs with their own event loop. Unlike Timer
+// it starts no goroutine: the caller registers its device, obtained with Fd or
+// SyscallConn, with poll or epoll, and calls Consume whenever it becomes
+// readable.
+type PollTimer struct {
+	c       *RTC
+	alarm   *timerAlarm
+	started suspendClock
+	fired   bool
+}
+
+// NewPollTimerAt creates a PollTimer that expires at the given time. The
+// device is opened with WithNonBlocking. WithPrecision and WithProgress are
+// not supported. If the alarm is skipped because t has passed and the past
+// alarm policy is PastAlarmFireNow, the device never becomes readable, so
+// callers using that policy should call Consume once before waiting.
+func NewPollTimerAt(dev string, t time.Time, opts ...TimerOption) (*PollTimer, error) {
+	c, o, err := openPollTimer(dev, opts)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.timerTarget(t, o.reference)
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return newPollTimer(c, target, time.Until(t), o)
+}
+
+// NewPollTimer creates a PollTimer that expires after duration d. See
+// NewPollTimerAt.
+func NewPollTimer(dev string, d time.Duration, opts ...TimerOption) (*PollTimer, error) {
+	c, o, err := openPollTimer(dev, opts)
+	if err != nil {
+		return nil, err
+	}
+	now, err := c.GetTime()
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return newPollTimer(c, now.Add(d), d, o)
+}
+
+// openPollTimer applies the options of a PollTimer and opens its device.
+func openPollTimer(dev string, opts []TimerOption) (c *RTC, o timerOptions, err error) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.precision != 0 || o.progress {
+		return nil, o, errors.New("poll timers do not support WithPrecision or WithProgress")
+	}
+	c, err = NewRTC(dev, append(o.rtcOpts, WithNonBlocking())...)
+	return c, o, err
+}
+
+// newPollTimer arms the alarm of a PollTimer expiring at t, which is d from
+// now. The device is closed on error.
+func newPollTimer(c *RTC, t time.Time, d time.Duration, o timerOptions) (*PollTimer, error) {
+	alarm, err := c.armTimerAlarm(t, d, o)
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return &PollTimer{
+		c:       c,
+		alarm:   alarm,
+		started: readSuspendClock(),
+	}, nil
+}
+
+// Fd returns the file descriptor to wait on for readability.
+// The descriptor remains owned by the PollTimer and is invalid after Close.
+func (t *PollTimer) Fd() uintptr {
+	return t.c.Fd()
+}
+
+// SyscallConn returns a raw connection to the PollTimer's device.
+// See RTC.SyscallConn.
+func (t *PollTimer) SyscallConn() (syscall.RawConn, error) {
+	return t.c.SyscallConn()
+}
+
+// Consume reads the pending interrupts without blocking, re-arming
+// intermediate alarms as needed. It returns the Alarm and true once the timer
+// has expired, and false while it has not or after the Alarm was returned.
+func (t *PollTimer) Consume() (a Alarm, ok bool, err error) {
+	if t.fired {
+		return Alarm{}, false, nil
+	}
+	var e Event
+	if t.alarm != nil {
+		for {
+			e, err = t.c.ReadEvent()
+			if errors.Is(err, syscall.EAGAIN) {
+				return Alarm{}, false, nil
+			}
+			if err != nil {
+				return Alarm{}, false, err
+			}
+			if !e.Alarm {
+				continue
+			}
+			if !t.alarm.hop {
+				break
+			}
+			if t.alarm.hop, err = t.c.armLongAlarm(t.alarm.t); err != nil {
+				return Alarm{}, false, err
+			}
+		}
+	}
+
+	t.fired = true
+	a = Alarm{
+		Time:      time.Now(),
+		Event:     e,
+		Suspended: readSuspendClock().suspendedSince(t.started),
+	}
+	a.RTCTime, _ = t.c.GetTime()
+	return a, true, nil
+}
+
+// Close disarms the alarm if the PollTimer has not expired and closes the
+// device.
+func (t *PollTimer) Close() (err error) {
+	if !t.fired && t.alarm != nil {
+		_ = t.c.SetAlarmInterrupt(false)
+	}
+	return t.c.Close()
+}
@@ -0,0 +1,59 @@
+package rtc
+
+import (
+	"encoding/binary"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestPollTimerConsume(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+	require.NoError(t, syscall.SetNonblock(c.fd(), true))
+	timer := &PollTimer{c: c, alarm: &timerAlarm{t: time.Now()}}
+	defer timer.Close()
+
+	_, ok, err := timer.Consume()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf[0:], 1<<8|unix.RTC_UF)
+	binary.LittleEndian.PutUint32(buf[4:], 1<<8|unix.RTC_AF)
+	_, err = w.Write(buf)
+	require.NoError(t, err)
+
+	a, ok, err := timer.Consume()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, a.Event.Alarm)
+
+	// The Alarm is only returned once
+	_, ok, err = timer.Consume()
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestPollTimer(t *testing.T) {
+	timer, err := NewPollTimer("/dev/rtc", time.Second)
+	require.NoError(t, err)
+	defer timer.Close()
+
+	fds := []unix.PollFd{{Fd: int32(timer.Fd()), Events: unix.POLLIN}}
+	n, err := unix.Poll(fds, 3000)
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "poll timer did not become readable in time")
+
+	_, ok, err := timer.Consume()
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
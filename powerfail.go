@@ -0,0 +1,48 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LastPowerFailure returns the time of the last power failure recorded by the
+// real-time clock, such as a switch-over to battery power.
+// Only drivers that expose a timestamp0 sysfs attribute record this, for
+// example those for the ISL1219, PCF2127 and RV-3028. For other devices the
+// returned error wraps ErrNotSupported. The zero time is returned if no event
+// has been recorded.
+func (c *RTC) LastPowerFailure() (t time.Time, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, p := range []string{
+		filepath.Join(dir, "timestamp0"),
+		filepath.Join(dir, "device", "timestamp0"),
+	} {
+		b, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read power failure timestamp: %w", err)
+		}
+		sec, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse power failure timestamp: %w", err)
+		}
+		if sec == 0 {
+			return time.Time{}, nil
+		}
+		return time.Unix(sec, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("failed to read power failure timestamp: %w", ErrNotSupported)
+}
@@ -0,0 +1,158 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procDriverRtcPath returns the location of /proc/driver/rtc, which reports
+// the state of the RTC that set the system clock at boot.
+func procDriverRtcPath() string {
+	return procPath("driver/rtc")
+}
+
+// ProcDriverInfo is the state of a real-time clock as reported by
+// /proc/driver/rtc. Fields the kernel or driver does not report are left at
+// their zero value, or nil for pointers.
+type ProcDriverInfo struct {
+	// Time is the RTC time.
+	Time time.Time
+	// Alarm is the alarm time. Fields the alarm does not match on are
+	// reported as asterisks by some drivers, in which case Alarm is zero.
+	Alarm time.Time
+	// AlarmInterrupt and AlarmPending are the alarm interrupt enable and
+	// pending states.
+	AlarmInterrupt *bool
+	AlarmPending   *bool
+	// UpdateInterrupt and PeriodicInterrupt are the interrupt enable states.
+	UpdateInterrupt   *bool
+	PeriodicInterrupt *bool
+	// Frequency is the periodic interrupt frequency.
+	Frequency uint
+	// MaxUserFrequency is the highest periodic interrupt frequency
+	// unprivileged users may set.
+	MaxUserFrequency uint
+	// BatteryOK reports the battery status, for drivers that report it.
+	BatteryOK *bool
+	// Extra holds the driver specific lines, keyed by name.
+	Extra map[string]string
+}
+
+// ProcInfo returns the state of the real-time clock reported by
+// /proc/driver/rtc. The kernel only reports the RTC that set the system clock
+// at boot; for other devices the returned error wraps ErrNotSupported.
+func (c *RTC) ProcInfo() (info ProcDriverInfo, err error) {
+	if hctosys, err := c.Hctosys(); err != nil {
+		return ProcDriverInfo{}, err
+	} else if !hctosys {
+		return ProcDriverInfo{}, fmt.Errorf("real-time clock not reported by %s: %w", procDriverRtcPath(), ErrNotSupported)
+	}
+	b, err := os.ReadFile(procDriverRtcPath())
+	if err != nil {
+		return ProcDriverInfo{}, fmt.Errorf("failed to read %s: %w", procDriverRtcPath(), err)
+	}
+	return parseProcDriverRtc(string(b))
+}
+
+// parseProcDriverRtc parses the contents of /proc/driver/rtc, accepting both
+// the format of the RTC class and the key names of the legacy PC driver.
+func parseProcDriverRtc(s string) (info ProcDriverInfo, err error) {
+	var rtcTime, rtcDate, alarmTime, alarmDate string
+	for _, l := range strings.Split(s, "\n") {
+		fields := strings.SplitN(l, ":", 2)
+		if len(fields) < 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+		switch key {
+		case "rtc_time":
+			rtcTime = value
+		case "rtc_date":
+			rtcDate = value
+		case "alrm_time", "alarm":
+			alarmTime = value
+		case "alrm_date":
+			alarmDate = value
+		case "alarm_IRQ":
+			info.AlarmInterrupt = parseProcBool(value)
+		case "alrm_pending":
+			info.AlarmPending = parseProcBool(value)
+		case "update IRQ enabled", "update_IRQ":
+			info.UpdateInterrupt = parseProcBool(value)
+		case "periodic IRQ enabled", "periodic_IRQ":
+			info.PeriodicInterrupt = parseProcBool(value)
+		case "periodic IRQ frequency", "periodic_freq":
+			if info.Frequency, err = parseProcUint(key, value); err != nil {
+				return ProcDriverInfo{}, err
+			}
+		case "max user IRQ frequency":
+			if info.MaxUserFrequency, err = parseProcUint(key, value); err != nil {
+				return ProcDriverInfo{}, err
+			}
+		case "batt_status":
+			info.BatteryOK = parseProcBool(value)
+		default:
+			if info.Extra == nil {
+				info.Extra = make(map[string]string)
+			}
+			info.Extra[key] = value
+		}
+	}
+	if rtcTime != "" {
+		if info.Time, err = parseProcTime(rtcDate, rtcTime); err != nil {
+			return ProcDriverInfo{}, err
+		}
+	}
+	if alarmTime != "" && !strings.Contains(alarmTime+alarmDate, "*") {
+		if info.Alarm, err = parseProcTime(alarmDate, alarmTime); err != nil {
+			return ProcDriverInfo{}, err
+		}
+	}
+	return info, nil
+}
+
+// parseProcTime parses a date and time of day reported by /proc/driver/rtc.
+// A missing date leaves the time on January 1, year 0.
+func parseProcTime(date, tod string) (t time.Time, err error) {
+	if date == "" {
+		t, err = time.Parse("15:04:05", tod)
+	} else {
+		t, err = time.Parse("2006-01-02 15:04:05", date+" "+tod)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %s: %w", procDriverRtcPath(), err)
+	}
+	return t, nil
+}
+
+// parseProcBool parses a yes/no or okay/dead value.
+func parseProcBool(s string) *bool {
+	b := s == "yes" || s == "okay"
+	return &b
+}
+
+// parseProcUint parses the numeric value of key.
+func parseProcUint(key, value string) (n uint, err error) {
+	v, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s %s: %w", procDriverRtcPath(), key, err)
+	}
+	return uint(v), nil
+}
+
+// procInterrupts returns the update and periodic interrupt enable states from
+// /proc/driver/rtc if it reports this device, the one that set the system
+// clock at boot.
+func (c *RTC) procInterrupts() (update, periodic *bool) {
+	info, err := c.ProcInfo()
+	if err != nil {
+		return nil, nil
+	}
+	return info.UpdateInterrupt, info.PeriodicInterrupt
+}
@@ -0,0 +1,73 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcDriverRtc(t *testing.T) {
+	info, err := parseProcDriverRtc(`rtc_time	: 05:06:07
+rtc_date	: 2030-03-04
+alrm_time	: 06:00:00
+alrm_date	: 2030-03-04
+alarm_IRQ	: no
+alrm_pending	: no
+update IRQ enabled	: yes
+periodic IRQ enabled	: no
+periodic IRQ frequency	: 1024
+max user IRQ frequency	: 64
+24hr		: yes
+`)
+	require.NoError(t, err)
+	assert.True(t, time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC).Equal(info.Time))
+	assert.True(t, time.Date(2030, time.March, 4, 6, 0, 0, 0, time.UTC).Equal(info.Alarm))
+	require.NotNil(t, info.AlarmInterrupt)
+	assert.False(t, *info.AlarmInterrupt)
+	require.NotNil(t, info.UpdateInterrupt)
+	assert.True(t, *info.UpdateInterrupt)
+	require.NotNil(t, info.PeriodicInterrupt)
+	assert.False(t, *info.PeriodicInterrupt)
+	assert.Equal(t, uint(1024), info.Frequency)
+	assert.Equal(t, uint(64), info.MaxUserFrequency)
+	assert.Nil(t, info.BatteryOK)
+	assert.Equal(t, map[string]string{"24hr": "yes"}, info.Extra)
+}
+
+func TestParseProcDriverRtcLegacy(t *testing.T) {
+	info, err := parseProcDriverRtc(`rtc_time	: 05:06:07
+rtc_date	: 2030-03-04
+alarm		: **:00:00
+DST_enable	: no
+update_IRQ	: no
+periodic_IRQ	: yes
+periodic_freq	: 64
+batt_status	: okay
+`)
+	require.NoError(t, err)
+	assert.True(t, info.Alarm.IsZero())
+	require.NotNil(t, info.PeriodicInterrupt)
+	assert.True(t, *info.PeriodicInterrupt)
+	assert.Equal(t, uint(64), info.Frequency)
+	require.NotNil(t, info.BatteryOK)
+	assert.True(t, *info.BatteryOK)
+	assert.Nil(t, info.AlarmInterrupt)
+
+	_, err = parseProcDriverRtc("rtc_time\t: 5 past 6\n")
+	assert.Error(t, err)
+}
+
+func TestProcInfo(t *testing.T) {
+	info, err := ProcInfo("/dev/rtc")
+	require.NoError(t, err)
+
+	tm, err := GetTime("/dev/rtc")
+	require.NoError(t, err)
+	assert.WithinDuration(t, tm, info.Time, time.Second, "time did not match the value read from the RTC")
+
+	freq, err := GetFrequency("/dev/rtc")
+	require.NoError(t, err)
+	assert.Equal(t, freq, info.Frequency, "frequency did not match the value read from the RTC")
+}
@@ -0,0 +1,110 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// minRTCTime is the earliest time accepted by the kernel's rtc_valid_tm.
+	minRTCTime = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+	// maxRTCTime bounds the times accepted when the device range is unknown.
+	// struct rtc_time reaches much further, but no driver does.
+	maxRTCTime = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+)
+
+// RangeError is returned when a time is outside the range the real-time clock
+// can represent.
+type RangeError struct {
+	// Time is the rejected time.
+	Time time.Time
+	// Min and Max bound the representable range.
+	Min time.Time
+	Max time.Time
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("time %v is outside the real-time clock range %v to %v", e.Time, e.Min, e.Max)
+}
+
+// timeRange returns the range of times the real-time clock can represent.
+// It is narrowed by the range sysfs attribute on kernels that expose the
+// device range, and cached.
+func (c *RTC) timeRange() (min, max time.Time) {
+	if !c.rangeMin.IsZero() {
+		return c.rangeMin, c.rangeMax
+	}
+
+	min, max = minRTCTime, maxRTCTime
+	if dir, err := c.sysfsDir(); err == nil {
+		if b, err := os.ReadFile(filepath.Join(dir, "range")); err == nil {
+			if lo, hi, ok := parseSysfsRange(string(b)); ok {
+				if lo.After(min) {
+					min = lo
+				}
+				if hi.Before(max) {
+					max = hi
+				}
+			}
+		}
+	}
+	c.rangeMin, c.rangeMax = min, max
+	return min, max
+}
+
+// parseSysfsRange parses the range sysfs attribute, formatted [min,max] in
+// seconds since the Unix epoch. The kernel reports max as an unsigned value,
+// which may exceed the range of time.Time; it is then capped.
+func parseSysfsRange(s string) (min, max time.Time, ok bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return time.Time{}, time.Time{}, false
+	}
+	fields := strings.Split(s[1:len(s)-1], ",")
+	if len(fields) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	lo, err := strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	hi, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	max = maxRTCTime
+	if hi < uint64(maxRTCTime.Unix()) {
+		max = time.Unix(int64(hi), 0).UTC()
+	}
+	return time.Unix(lo, 0).UTC(), max, true
+}
+
+// readSysfsTime reads a sysfs attribute holding seconds since the Unix epoch.
+func readSysfsTime(path string) (t time.Time, ok bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0).UTC(), true
+}
+
+// checkRange returns a *RangeError if t cannot be represented by the
+// real-time clock.
+func (c *RTC) checkRange(t time.Time) (err error) {
+	min, max := c.timeRange()
+	if t.Before(min) || t.After(max) {
+		return &RangeError{Time: t, Min: min, Max: max}
+	}
+	return nil
+}
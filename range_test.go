@@ -0,0 +1,67 @@
+package rtc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRtcCheckRange(t *testing.T) {
+	c := &RTC{
+		rangeMin: minRTCTime,
+		rangeMax: time.Date(2099, time.December, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	assert.NoError(t, c.checkRange(time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)))
+
+	for _, tm := range []time.Time{
+		time.Date(1899, time.December, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, time.December, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		err := c.checkRange(tm)
+		var rangeErr *RangeError
+		require.True(t, errors.As(err, &rangeErr), "%v", tm)
+		assert.True(t, tm.Equal(rangeErr.Time))
+		assert.True(t, c.rangeMax.Equal(rangeErr.Max))
+	}
+}
+
+func TestRtcTimeRangeSysfs(t *testing.T) {
+	sys := t.TempDir()
+	SetRoots("", sys, "")
+	defer SetRoots("", "", "")
+	dir := filepath.Join(sys, "class", "rtc", "rtc0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	// Pipes have device number 0:0.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dev"), []byte("0:0\n"), 0644))
+	// The range of a chip with a two-digit year, 2000 to 2099
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "range"), []byte("[946684800,4102444799]\n"), 0644))
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+	defer c.Close()
+
+	min, max := c.timeRange()
+	assert.True(t, time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC).Equal(min), "%v", min)
+	assert.True(t, time.Date(2099, time.December, 31, 23, 59, 59, 0, time.UTC).Equal(max), "%v", max)
+}
+
+func TestParseSysfsRange(t *testing.T) {
+	min, max, ok := parseSysfsRange("[0,18446744073709551615]\n")
+	require.True(t, ok)
+	assert.True(t, minRTCTime.Equal(min))
+	assert.True(t, maxRTCTime.Equal(max))
+
+	for _, s := range []string{"", "0,1", "[0]", "[a,1]", "[0,-1]"} {
+		_, _, ok := parseSysfsRange(s)
+		assert.False(t, ok, "%q", s)
+	}
+}
@@ -0,0 +1,85 @@
+package rtc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordStream(t *testing.T) {
+	now := time.Now()
+	tick := Tick{
+		Time:   now,
+		Delta:  500 * time.Millisecond,
+		Frame:  1,
+		Missed: 2,
+	}
+	alarm := Alarm{
+		Time:      now.Add(time.Minute),
+		RTCTime:   now.Add(time.Minute).Truncate(time.Second),
+		Event:     Event{Alarm: true, Update: true, Count: 2},
+		Suspended: true,
+		Missed:    true,
+	}
+
+	var buf bytes.Buffer
+	w := NewRecordWriter(&buf)
+	require.NoError(t, w.WriteTick(tick))
+	require.NoError(t, w.WriteAlarm(alarm))
+	// Zero times are kept
+	unread := alarm
+	unread.RTCTime = time.Time{}
+	require.NoError(t, w.WriteAlarm(unread))
+
+	r := NewRecordReader(bytes.NewReader(buf.Bytes()))
+
+	rec, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, RecordTick, rec.Type)
+	assert.True(t, tick.Time.Equal(rec.Tick.Time))
+	assert.Equal(t, tick.Delta, rec.Tick.Delta)
+	assert.Equal(t, tick.Frame, rec.Tick.Frame)
+	assert.Equal(t, tick.Missed, rec.Tick.Missed)
+
+	rec, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, RecordAlarm, rec.Type)
+	assert.True(t, alarm.Time.Equal(rec.Alarm.Time))
+	assert.True(t, alarm.RTCTime.Equal(rec.Alarm.RTCTime))
+	assert.Equal(t, alarm.Event, rec.Alarm.Event)
+	assert.True(t, rec.Alarm.Suspended)
+	assert.True(t, rec.Alarm.Missed)
+
+	rec, err = r.Next()
+	require.NoError(t, err)
+	assert.True(t, rec.Alarm.RTCTime.IsZero())
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+
+	// A stream cut within a record is reported as unexpected
+	r = NewRecordReader(bytes.NewReader(buf.Bytes()[:10]))
+	_, err = r.Next()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	r = NewRecordReader(bytes.NewReader(append([]byte(recordMagic), recordVersion, 0xFF)))
+	_, err = r.Next()
+	assert.Error(t, err)
+
+	// Streams without a header or of a later version are rejected
+	r = NewRecordReader(bytes.NewReader(buf.Bytes()[len(recordMagic)+1:]))
+	_, err = r.Next()
+	assert.Error(t, err)
+	r = NewRecordReader(bytes.NewReader(append([]byte(recordMagic), recordVersion+1)))
+	_, err = r.Next()
+	assert.Error(t, err)
+
+	// An empty stream has no records
+	r = NewRecordReader(bytes.NewReader(nil))
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
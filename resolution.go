@@ -0,0 +1,117 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// minuteAlarmDrivers lists drivers whose alarms ignore seconds, for kernels
+// that predate RTC_PARAM_GET.
+var minuteAlarmDrivers = []string{
+	"pcf2123",
+	"pcf8523",
+	"pcf8563",
+	"rv3028",
+	"rv3032",
+	"rv8803",
+}
+
+// AlarmResolution returns the granularity of the real-time clock's alarms,
+// such as one second or one minute for chips that only match on minutes.
+// The resolution is read from the device features on kernels that support
+// RTC_PARAM_GET, and otherwise from a list of known drivers. It defaults to one
+// second when it cannot be determined. The result is cached.
+func (c *RTC) AlarmResolution() (resolution time.Duration, err error) {
+	if c.alarmRes != 0 {
+		return c.alarmRes, nil
+	}
+
+	resolution = time.Second
+	if c.CheckFeature(FeatureParam) == nil {
+		features, err := c.GetParamFeatures()
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case features.Has(DeviceFeatureAlarmResMinute):
+			resolution = time.Minute
+		case features.Has(DeviceFeatureAlarmRes2s):
+			resolution = 2 * time.Second
+		}
+	} else if dir, err := c.sysfsDir(); err == nil {
+		if b, err := os.ReadFile(filepath.Join(dir, "name")); err == nil {
+			name := string(b)
+			for _, d := range minuteAlarmDrivers {
+				if strings.Contains(name, d) {
+					resolution = time.Minute
+					break
+				}
+			}
+		}
+	}
+
+	c.alarmRes = resolution
+	return resolution, nil
+}
+
+// AlarmRounding selects how alarm times that are not a multiple of the
+// device's alarm resolution are handled.
+type AlarmRounding int
+
+const (
+	// AlarmRoundDown rounds alarm times down, which is what the hardware
+	// does. The alarm may fire up to one resolution step early.
+	AlarmRoundDown AlarmRounding = iota
+	// AlarmRoundUp rounds alarm times up. The alarm may fire up to one
+	// resolution step late, but never early.
+	AlarmRoundUp
+	// AlarmRoundError rejects alarm times that would need rounding with an
+	// error wrapping ErrAlarmResolution.
+	AlarmRoundError
+)
+
+// ErrAlarmResolution is returned when an alarm time cannot be represented at
+// the device's alarm resolution and the rounding policy is AlarmRoundError.
+var ErrAlarmResolution = errors.New("alarm time is finer than the real-time clock's alarm resolution")
+
+// SetAlarmRounding sets how SetAlarm and SetWakeAlarm handle alarm times that
+// are not a multiple of the device's alarm resolution. The default is
+// AlarmRoundDown.
+func (c *RTC) SetAlarmRounding(r AlarmRounding) {
+	c.rounding = r
+}
+
+// effectiveAlarm returns the alarm time the device will actually use for t,
+// after applying the past alarm and rounding policies.
+func (c *RTC) effectiveAlarm(t time.Time) (effective time.Time, err error) {
+	if t, err = c.checkPastAlarm(t); err != nil {
+		return time.Time{}, err
+	}
+	resolution, err := c.AlarmResolution()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return roundAlarm(t, resolution, c.rounding)
+}
+
+func roundAlarm(t time.Time, resolution time.Duration, r AlarmRounding) (rounded time.Time, err error) {
+	rounded = t.Truncate(resolution)
+	if rounded.Equal(t) {
+		return t, nil
+	}
+	switch r {
+	case AlarmRoundUp:
+		return rounded.Add(resolution), nil
+	case AlarmRoundError:
+		return time.Time{}, fmt.Errorf("%w: %v is not a multiple of %v", ErrAlarmResolution, t, resolution)
+	default:
+		return rounded, nil
+	}
+}
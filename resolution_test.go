@@ -0,0 +1,32 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundAlarm(t *testing.T) {
+	exact := time.Date(2030, time.January, 2, 3, 4, 0, 0, time.UTC)
+	between := exact.Add(30 * time.Second)
+
+	for _, r := range []AlarmRounding{AlarmRoundDown, AlarmRoundUp, AlarmRoundError} {
+		rounded, err := roundAlarm(exact, time.Minute, r)
+		require.NoError(t, err)
+		assert.True(t, exact.Equal(rounded))
+	}
+
+	rounded, err := roundAlarm(between, time.Minute, AlarmRoundDown)
+	require.NoError(t, err)
+	assert.True(t, exact.Equal(rounded))
+
+	rounded, err = roundAlarm(between, time.Minute, AlarmRoundUp)
+	require.NoError(t, err)
+	assert.True(t, exact.Add(time.Minute).Equal(rounded))
+
+	_, err = roundAlarm(between, time.Minute, AlarmRoundError)
+	assert.True(t, errors.Is(err, ErrAlarmResolution))
+}
@@ -0,0 +1,98 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrWakeAlarmLost is returned by VerifyWakeAlarm when the real-time clock no
+// longer holds the wake alarm that was recorded by SaveWakeAlarm.
+var ErrWakeAlarmLost = errors.New("real-time clock lost its wake alarm")
+
+// wakeAlarmFingerprint identifies a programmed wake alarm.
+type wakeAlarmFingerprint struct {
+	enabled bool
+	t       time.Time
+}
+
+func (f wakeAlarmFingerprint) String() string {
+	return fmt.Sprintf("%t %d\n", f.enabled, f.t.Unix())
+}
+
+func parseWakeAlarmFingerprint(s string) (f wakeAlarmFingerprint, err error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return f, fmt.Errorf("malformed wake alarm fingerprint %q", s)
+	}
+	if f.enabled, err = strconv.ParseBool(fields[0]); err != nil {
+		return f, fmt.Errorf("malformed wake alarm fingerprint %q: %w", s, err)
+	}
+	sec, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return f, fmt.Errorf("malformed wake alarm fingerprint %q: %w", s, err)
+	}
+	f.t = time.Unix(sec, 0).UTC()
+	return f, nil
+}
+
+// SaveWakeAlarm records a fingerprint of the currently programmed wake alarm
+// in the state file at path so that it can be checked by VerifyWakeAlarm after
+// a restart or power cycle.
+func (c *RTC) SaveWakeAlarm(path string) (err error) {
+	a, err := c.WakeAlarm()
+	if err != nil {
+		return err
+	}
+	f := wakeAlarmFingerprint{enabled: a.Enabled, t: a.Time}
+	if err := os.WriteFile(path, []byte(f.String()), 0644); err != nil {
+		return fmt.Errorf("failed to save wake alarm fingerprint: %w", err)
+	}
+	return nil
+}
+
+// VerifyWakeAlarm checks that the real-time clock still holds the wake alarm
+// recorded in the state file at path by SaveWakeAlarm.
+// Some chips lose their alarm registers when switching to battery power,
+// which would otherwise go unnoticed until the expected wake never happens.
+// An alarm that was disabled or whose time has already passed is not checked.
+// If the alarm was lost, the returned error wraps ErrWakeAlarmLost.
+func (c *RTC) VerifyWakeAlarm(path string) (err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read wake alarm fingerprint: %w", err)
+	}
+	saved, err := parseWakeAlarmFingerprint(string(b))
+	if err != nil {
+		return err
+	}
+	if !saved.enabled {
+		return nil
+	}
+
+	now, err := c.GetTime()
+	if err != nil {
+		return err
+	}
+	if !saved.t.After(now) {
+		return nil
+	}
+
+	a, err := c.WakeAlarm()
+	if err != nil {
+		return err
+	}
+	if !a.Enabled {
+		return fmt.Errorf("%w: expected alarm at %v, found alarm disabled", ErrWakeAlarmLost, saved.t)
+	}
+	if !a.Time.Equal(saved.t) {
+		return fmt.Errorf("%w: expected alarm at %v, found alarm at %v", ErrWakeAlarmLost, saved.t, a.Time)
+	}
+	return nil
+}
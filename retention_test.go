@@ -0,0 +1,26 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWakeAlarmFingerprint(t *testing.T) {
+	f := wakeAlarmFingerprint{
+		enabled: true,
+		t:       time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC),
+	}
+
+	parsed, err := parseWakeAlarmFingerprint(f.String())
+	require.NoError(t, err)
+	assert.Equal(t, f.enabled, parsed.enabled)
+	assert.True(t, f.t.Equal(parsed.t))
+
+	_, err = parseWakeAlarmFingerprint("true")
+	assert.Error(t, err)
+	_, err = parseWakeAlarmFingerprint("yes 1234")
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// roots holds the locations of the device, sysfs and procfs trees.
+var roots = struct {
+	sync.RWMutex
+	dev, sys, proc string
+}{
+	dev:  "/dev",
+	sys:  "/sys",
+	proc: "/proc",
+}
+
+// SetRoots sets alternate locations of /dev, /sys and /proc, for agents
+// running in containers or chroots with the host's trees mounted elsewhere,
+// such as "/host/dev". Discovery, the sysfs and procfs features and the device
+// nodes derived from them use the new locations. Empty arguments restore the
+// defaults.
+func SetRoots(dev, sys, proc string) {
+	if dev == "" {
+		dev = "/dev"
+	}
+	if sys == "" {
+		sys = "/sys"
+	}
+	if proc == "" {
+		proc = "/proc"
+	}
+	roots.Lock()
+	defer roots.Unlock()
+	roots.dev, roots.sys, roots.proc = dev, sys, proc
+}
+
+// devDir returns the location of /dev.
+func devDir() string {
+	roots.RLock()
+	defer roots.RUnlock()
+	return roots.dev
+}
+
+// rtcClassDir returns the sysfs directory containing the rtc class devices.
+func rtcClassDir() string {
+	roots.RLock()
+	defer roots.RUnlock()
+	return filepath.Join(roots.sys, "class", "rtc")
+}
+
+// procPath returns the location of the file name in /proc.
+func procPath(name string) string {
+	roots.RLock()
+	defer roots.RUnlock()
+	return filepath.Join(roots.proc, name)
+}
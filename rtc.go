@@ -21,7 +21,9 @@
 package rtc
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"syscall"
 	"time"
 	"unsafe"
@@ -42,51 +44,204 @@ type timeRtc struct {
 }
 
 func (t timeRtc) rtcTime() *unix.RTCTime {
-	return &unix.RTCTime{
+	tm := &unix.RTCTime{
 		Sec:  int32(t.Second()),
 		Min:  int32(t.Minute()),
 		Hour: int32(t.Hour()),
 		Mday: int32(t.Day()),
 		Mon:  int32(t.Month() - 1),
 		Year: int32(t.Year() - 1900),
+		Wday: int32(t.Weekday()),
+		Yday: int32(t.YearDay() - 1),
 	}
+	if t.IsDST() {
+		tm.Isdst = 1
+	}
+	return tm
+}
+
+// ToRTCTime converts t to the kernel's struct rtc_time in UTC, with the week
+// day and year day populated for drivers that check them.
+func ToRTCTime(t time.Time) unix.RTCTime {
+	return *timeRtc{Time: t.UTC()}.rtcTime()
+}
+
+// FromRTCTime converts the kernel's struct rtc_time, holding a UTC time, to a
+// time.Time. The week day, year day and DST fields are ignored.
+func FromRTCTime(tm unix.RTCTime) time.Time {
+	return rtcTime{tm}.time()
+}
+
+// truncated returns the time as it is stored by the RTC, in UTC with a
+// resolution of one second.
+func (t timeRtc) truncated() time.Time {
+	return rtcTime{*timeRtc{Time: t.UTC()}.rtcTime()}.time()
 }
 
 type RTC struct {
-	fd int
+	f *os.File
+
+	// loc is the time zone the RTC keeps time in, UTC if nil.
+	loc *time.Location
+	// rangeMin and rangeMax cache the representable time range once read.
+	rangeMin time.Time
+	rangeMax time.Time
+
+	// alarmRes caches the alarm resolution once detected.
+	alarmRes time.Duration
+	// rounding is applied to alarm times that are not a multiple of alarmRes.
+	rounding AlarmRounding
+	// pastAlarm is applied to alarm times that are not in the future.
+	pastAlarm PastAlarmPolicy
+
+	// conn wakes the raw connections waiting for the device on Close.
+	conn connWaiter
+}
+
+// Option configures how NewRTC opens a real-time clock device.
+type Option func(*rtcOptions)
+
+type rtcOptions struct {
+	readOnly    bool
+	loc         *time.Location
+	adjtime     bool
+	inherit     bool
+	nonBlocking bool
+}
+
+// WithReadOnly opens the device read-only, which does not require write
+// permission on the device node. This suffices for reading the time, the
+// alarms and the interrupts, allowing unprivileged users to use GetTime.
+func WithReadOnly() Option {
+	return func(o *rtcOptions) {
+		o.readOnly = true
+	}
+}
+
+// WithLocalTime interprets and writes the RTC time as local time instead of
+// UTC, like hwclock --localtime, for machines that dual-boot Windows.
+func WithLocalTime() Option {
+	return func(o *rtcOptions) {
+		o.loc = time.Local
+	}
+}
+
+// WithAdjtime interprets the RTC time as local time or UTC according to the
+// mode recorded by hwclock in /etc/adjtime. UTC is assumed if the file does
+// not exist.
+func WithAdjtime() Option {
+	return func(o *rtcOptions) {
+		o.adjtime = true
+	}
+}
+
+// WithInheritable opens the device without O_CLOEXEC, so that child processes
+// started with exec inherit it. By default the device is closed on exec.
+func WithInheritable() Option {
+	return func(o *rtcOptions) {
+		o.inherit = true
+	}
+}
+
+// WithNonBlocking opens the device with O_NONBLOCK for use in poll driven
+// event loops, for example through SyscallConn. Reading an interrupt with
+// ReadEvent then fails immediately with an error wrapping syscall.EAGAIN when
+// none is pending instead of blocking.
+func WithNonBlocking() Option {
+	return func(o *rtcOptions) {
+		o.nonBlocking = true
+	}
 }
 
 // NewRTC opens a real-time clock device.
-func NewRTC(dev string) (*RTC, error) {
-	fd, err := syscall.Open(dev, syscall.O_RDWR, uint32(0600))
+func NewRTC(dev string, opts ...Option) (*RTC, error) {
+	o := rtcOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	mode := syscall.O_RDWR
+	if o.readOnly {
+		mode = syscall.O_RDONLY
+	}
+	if !o.inherit {
+		mode |= syscall.O_CLOEXEC
+	}
+	if o.nonBlocking {
+		mode |= syscall.O_NONBLOCK
+	}
+	if o.adjtime {
+		if b, err := os.ReadFile(adjtimePath); err == nil && parseAdjtimeLocal(string(b)) {
+			o.loc = time.Local
+		}
+	}
+	fd, err := syscall.Open(dev, mode, uint32(0600))
 	if err != nil {
-		return nil, fmt.Errorf("failed to open rtc: %w", err)
+		return nil, fmt.Errorf("failed to open rtc: %w", wrapErrno(err))
 	}
 	return &RTC{
-		fd: fd,
+		f:   os.NewFile(uintptr(fd), dev),
+		loc: o.loc,
 	}, nil
 }
 
-// Close closes a real-time clock device.
+// fd returns the file descriptor of the device, or -1 once it is closed.
+// Unlike os.File.Fd, it leaves the blocking mode of the descriptor untouched.
+func (c *RTC) fd() int {
+	fd := -1
+	if rc, err := c.f.SyscallConn(); err == nil {
+		_ = rc.Control(func(s uintptr) {
+			fd = int(s)
+		})
+	}
+	return fd
+}
+
+// fromRTC converts a time read from the RTC, which is in the RTC's time zone.
+// The result is in UTC.
+func (c *RTC) fromRTC(tm unix.RTCTime) time.Time {
+	t := rtcTime{tm}.time()
+	if c.loc == nil {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, c.loc).UTC()
+}
+
+// toRTC converts a time to be written to the RTC in the RTC's time zone.
+func (c *RTC) toRTC(t time.Time) *unix.RTCTime {
+	loc := c.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	return timeRtc{Time: t.In(loc)}.rtcTime()
+}
+
+// Close closes a real-time clock device. Operations on a closed device return
+// an error wrapping ErrClosed. Closing the device again returns ErrClosed and
+// has no other effect.
 func (c *RTC) Close() (err error) {
-	err = syscall.Close(c.fd)
-	c.fd = 0
-	return err
+	c.conn.close()
+	if err := c.f.Close(); err != nil {
+		if errors.Is(err, os.ErrClosed) {
+			return ErrClosed
+		}
+		return fmt.Errorf("failed to close rtc: %w", err)
+	}
+	return nil
 }
 
 // GetEpoch returns the real-time clock's epoch.
 func (c *RTC) GetEpoch() (epoch uint, err error) {
 	e := new(uint32)
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_EPOCH_READ, uintptr(unsafe.Pointer(e))); errno != 0 {
-		return 0, fmt.Errorf("failed to read real-time clock epoch: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_EPOCH_READ, uintptr(unsafe.Pointer(e))); errno != 0 {
+		return 0, fmt.Errorf("failed to read real-time clock epoch: %w", wrapErrno(errno))
 	}
 	return uint(*e), nil
 }
 
 // SetEpoch sets the real-time clock's epoch.
 func (c *RTC) SetEpoch(epoch uint) (err error) {
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_EPOCH_SET, uintptr(epoch)); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock epoch: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_EPOCH_SET, uintptr(epoch)); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock epoch: %w", wrapErrno(errno))
 	}
 	return nil
 }
@@ -94,34 +249,93 @@ func (c *RTC) SetEpoch(epoch uint) (err error) {
 // GetTime returns the specified real-time clock device time.
 func (c *RTC) GetTime() (t time.Time, err error) {
 	tm := new(rtcTime)
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_RD_TIME, uintptr(unsafe.Pointer(tm))); errno != 0 {
-		return time.Time{}, fmt.Errorf("failed to read real-time clock time: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_RD_TIME, uintptr(unsafe.Pointer(tm))); errno != 0 {
+		return time.Time{}, fmt.Errorf("failed to read real-time clock time: %w", wrapErrno(errno))
 	}
-	return tm.time(), nil
+	return c.fromRTC(tm.RTCTime), nil
+}
+
+// GetTimeSynced waits for the next update interrupt, which occurs when the
+// RTC's seconds counter increments, and returns the RTC time along with the
+// system time at which the edge was observed, like hwclock does.
+// Since the RTC time is exact at the edge, this correlates the RTC with the
+// system clock to within the interrupt latency instead of up to a second.
+// The edge time carries a monotonic clock reading, so it can be compared with
+// time.Since regardless of later system clock adjustments.
+// If no update interrupt occurs within two seconds, the returned error wraps
+// ErrTimeout.
+func (c *RTC) GetTimeSynced() (t time.Time, edge time.Time, err error) {
+	if err := c.SetUpdateInterrupt(true); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	defer func() {
+		_ = c.SetUpdateInterrupt(false)
+	}()
+
+	for {
+		e, err := c.WaitForInterrupt(2 * time.Second)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		edge = time.Now()
+		if e.Update {
+			break
+		}
+	}
+
+	t, err = c.GetTime()
+	return t, edge, err
 }
 
 // SetTime sets the time for the specified real-time clock device.
+// If t is outside the range the device can represent, a *RangeError is
+// returned.
 func (c *RTC) SetTime(t time.Time) (err error) {
-	tm := timeRtc{Time: t}.rtcTime()
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_SET_TIME, uintptr(unsafe.Pointer(tm))); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock time: %w", errno)
+	if err := c.checkRange(t); err != nil {
+		return err
+	}
+	tm := c.toRTC(t)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_SET_TIME, uintptr(unsafe.Pointer(tm))); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock time: %w", wrapErrno(errno))
 	}
 	return nil
 }
 
+// SetTimeSynced sets the real-time clock to t with sub-second accuracy.
+// The RTC only stores whole seconds, and most chips restart their second when
+// the time is written, so SetTime leaves the RTC up to a second behind. Instead
+// SetTimeSynced waits until t, advanced by the time elapsed since the call,
+// crosses a second boundary and writes that second, leaving the RTC within
+// about 10ms of the intended time.
+func (c *RTC) SetTimeSynced(t time.Time) (err error) {
+	start := time.Now()
+	time.Sleep(untilNextSecond(t) - time.Since(start))
+	return c.SetTime(t.Add(time.Since(start)).Round(time.Second))
+}
+
+// untilNextSecond returns the time remaining until t reaches the next whole
+// second, or zero if t is a whole second.
+func untilNextSecond(t time.Time) time.Duration {
+	frac := time.Duration(t.Nanosecond())
+	if frac == 0 {
+		return 0
+	}
+	return time.Second - frac
+}
+
 // GetFrequency returns the periodic interrupt frequency.
 func (c *RTC) GetFrequency() (frequency uint, err error) {
 	f := new(uint)
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_IRQP_READ, uintptr(unsafe.Pointer(f))); errno != 0 {
-		return 0, fmt.Errorf("failed to read real-time clock frequency: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_IRQP_READ, uintptr(unsafe.Pointer(f))); errno != 0 {
+		return 0, fmt.Errorf("failed to read real-time clock frequency: %w", wrapErrno(errno))
 	}
 	return *f, nil
 }
 
 // SetFrequency sets the frequency of the real-time clock's periodic interrupt.
 func (c *RTC) SetFrequency(frequency uint) (err error) {
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_IRQP_SET, uintptr(frequency)); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock frequency: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_IRQP_SET, uintptr(frequency)); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock frequency: %w", wrapErrno(errno))
 	}
 	return nil
 }
@@ -132,20 +346,21 @@ func (c *RTC) SetPeriodicInterrupt(enable bool) (err error) {
 	if !enable {
 		op = unix.RTC_PIE_OFF
 	}
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), uintptr(op), 0); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock interrupts: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), uintptr(op), 0); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock interrupts: %w", wrapErrno(errno))
 	}
 	return nil
 }
 
-// SetAlarmInterrupt enables or disables the real-time clock's alarm interrupt.
+// SetAlarmInterrupt enables or disables the real-time clock's alarm interrupt,
+// which arms or disarms the standard alarm. See AlarmKind.
 func (c *RTC) SetAlarmInterrupt(enable bool) (err error) {
 	op := unix.RTC_AIE_ON
 	if !enable {
 		op = unix.RTC_AIE_OFF
 	}
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), uintptr(op), 0); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock alarm interrupt: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), uintptr(op), 0); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock alarm interrupt: %w", wrapErrno(errno))
 	}
 	return nil
 }
@@ -156,59 +371,207 @@ func (c *RTC) SetUpdateInterrupt(enable bool) (err error) {
 	if !enable {
 		op = unix.RTC_UIE_OFF
 	}
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), uintptr(op), 0); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock update interrupt: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), uintptr(op), 0); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock update interrupt: %w", wrapErrno(errno))
 	}
 	return nil
 }
 
-// GetAlarm returns the real-time clock's alarm time.
+// setInterrupt enables or disables the given interrupt.
+func (c *RTC) setInterrupt(i Interrupt, enable bool) (err error) {
+	switch i {
+	case InterruptAlarm:
+		return c.SetAlarmInterrupt(enable)
+	case InterruptUpdate:
+		return c.SetUpdateInterrupt(enable)
+	case InterruptPeriodic:
+		return c.SetPeriodicInterrupt(enable)
+	default:
+		return fmt.Errorf("unknown real-time clock interrupt %s", i)
+	}
+}
+
+// GetAlarm returns the real-time clock's standard alarm time, read with
+// RTC_ALM_READ. See AlarmKind.
 func (c *RTC) GetAlarm() (t time.Time, err error) {
 	tm := new(rtcTime)
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_ALM_READ, uintptr(unsafe.Pointer(tm))); errno != 0 {
-		return time.Time{}, fmt.Errorf("failed to read real-time clock alarm: %w", errno)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_ALM_READ, uintptr(unsafe.Pointer(tm))); errno != 0 {
+		return time.Time{}, fmt.Errorf("failed to read real-time clock alarm: %w", wrapErrno(errno))
 	}
-	return tm.time(), nil
+	return c.fromRTC(tm.RTCTime), nil
 }
 
-// SetAlarm sets the real-time clock's alarm time.
-func (c *RTC) SetAlarm(t time.Time) (err error) {
-	tm := timeRtc{Time: t}.rtcTime()
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_ALM_SET, uintptr(unsafe.Pointer(tm))); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock alarm: %w", errno)
+// SetAlarm sets the real-time clock's standard alarm time with RTC_ALM_SET.
+// Only the time of day is used, so the alarm fires within 24 hours of the
+// current RTC time, and t must not be further ahead; otherwise a *RangeError
+// is returned. Use SetWakeAlarm for alarms further ahead. The alarm fires once
+// the alarm interrupt is enabled with SetAlarmInterrupt. See AlarmKind.
+// It returns the effective alarm time, which is t rounded to the alarm
+// resolution of the device according to the policy set with SetAlarmRounding.
+func (c *RTC) SetAlarm(t time.Time) (effective time.Time, err error) {
+	now, err := c.GetTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if max := now.Add(24 * time.Hour); t.After(max) {
+		return time.Time{}, &RangeError{Time: t, Min: now, Max: max}
+	}
+	t, err = c.effectiveAlarm(t)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tm := c.toRTC(t)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_ALM_SET, uintptr(unsafe.Pointer(tm))); errno != 0 {
+		return time.Time{}, fmt.Errorf("failed to set real-time clock alarm: %w", wrapErrno(errno))
+	}
+	return t, nil
+}
+
+// SetDailyAlarm sets the real-time clock's standard alarm with RTC_ALM_SET to
+// fire at the given time of day, in the time zone the RTC keeps, within the
+// next 24 hours. If the time of day has already passed today, the alarm fires
+// tomorrow. The alarm fires once the alarm interrupt is enabled with
+// SetAlarmInterrupt. Devices with minute alarm resolution ignore sec.
+func (c *RTC) SetDailyAlarm(hour, min, sec int) (err error) {
+	if hour < 0 || hour > 23 || min < 0 || min > 59 || sec < 0 || sec > 59 {
+		return fmt.Errorf("invalid daily alarm time %02d:%02d:%02d", hour, min, sec)
+	}
+	tm := &unix.RTCTime{
+		Sec:  int32(sec),
+		Min:  int32(min),
+		Hour: int32(hour),
+		// The date is ignored by RTC_ALM_SET.
+		Mday: 1,
+		Year: 70,
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_ALM_SET, uintptr(unsafe.Pointer(tm))); errno != 0 {
+		return fmt.Errorf("failed to set real-time clock alarm: %w", wrapErrno(errno))
 	}
 	return nil
 }
 
+// WaitAlarm blocks until the real-time clock's alarm interrupt occurs.
+// The alarm interrupt must be enabled with SetAlarmInterrupt. Other interrupts
+// received while waiting are discarded.
+func (c *RTC) WaitAlarm() (err error) {
+	for {
+		e, err := c.ReadEvent()
+		if err != nil {
+			return err
+		}
+		if e.Alarm {
+			return nil
+		}
+	}
+}
+
 // GetWakeAlarm returns the real-time clock's wake alarm time.
+//
+// Deprecated: Use WakeAlarm, which returns the state as a struct. Before it
+// was reimplemented on WakeAlarm, GetWakeAlarm read the standard alarm with
+// RTC_ALM_READ and returned meaningless enabled and pending flags.
 func (c *RTC) GetWakeAlarm() (enabled bool, pending bool, t time.Time, err error) {
-	a := new(unix.RTCWkAlrm)
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_ALM_READ, uintptr(unsafe.Pointer(a))); errno != 0 {
-		return false, false, time.Time{}, fmt.Errorf("failed to read real-time clock wake alarm: %w", errno)
+	a, err := c.WakeAlarm()
+	if err != nil {
+		return false, false, time.Time{}, err
 	}
-	return a.Enabled == 1, a.Pending == 1, rtcTime{a.Time}.time(), nil
+	return a.Enabled, a.Pending, a.Time, nil
+}
+
+// SetWakeAlarm sets and enables the real-time clock's wake alarm with
+// RTC_WKALM_SET. See AlarmKind.
+// It returns the effective alarm time, which is t rounded to the alarm
+// resolution of the device according to the policy set with SetAlarmRounding.
+func (c *RTC) SetWakeAlarm(t time.Time) (effective time.Time, err error) {
+	return c.SetWakeAlarmEnabled(t, true)
 }
 
-// SetWakeAlarm sets the real-time clock's wake alarm time.
-func (c *RTC) SetWakeAlarm(t time.Time) (err error) {
+// SetWakeAlarmIn sets and enables the real-time clock's wake alarm to fire
+// after the duration d, measured from the current RTC time like rtcwake does.
+// It returns the effective alarm time.
+func (c *RTC) SetWakeAlarmIn(d time.Duration) (effective time.Time, err error) {
+	now, err := c.GetTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.SetWakeAlarm(now.Add(d))
+}
+
+// SetWakeAlarmEnabled sets the real-time clock's wake alarm with
+// RTC_WKALM_SET, armed only if enabled is true. A disabled alarm is programmed
+// without firing, and can be armed later with SetAlarmInterrupt.
+// It returns the effective alarm time. If t is outside the range the device
+// can represent, a *RangeError is returned. If the driver does not support
+// RTC_WKALM_SET, an enabled alarm is set with SetSysfsWakeAlarm instead.
+func (c *RTC) SetWakeAlarmEnabled(t time.Time, enabled bool) (effective time.Time, err error) {
+	if err := c.checkRange(t); err != nil {
+		return time.Time{}, err
+	}
+	t, err = c.effectiveAlarm(t)
+	if err != nil {
+		return time.Time{}, err
+	}
 	a := &unix.RTCWkAlrm{
-		Enabled: 1,
-		Time:    *timeRtc{Time: t}.rtcTime(),
+		Time: *c.toRTC(t),
 	}
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_WKALM_SET, uintptr(unsafe.Pointer(a))); errno != 0 {
-		return fmt.Errorf("failed to set real-time clock wake alarm: %w", errno)
+	if enabled {
+		a.Enabled = 1
 	}
-	return nil
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_WKALM_SET, uintptr(unsafe.Pointer(a))); errno != 0 {
+		if enabled {
+			if effective, ok, err := c.wakeAlarmFallback(errno, t); ok {
+				return effective, err
+			}
+		}
+		return time.Time{}, fmt.Errorf("failed to set real-time clock wake alarm: %w", wrapErrno(errno))
+	}
+	return t, nil
 }
 
-// CancelWakeAlarm cancels the real-time clock's wake alarm.
+// CancelWakeAlarm cancels the real-time clock's wake alarm. Like rtcwake, it
+// reads the current alarm with RTC_WKALM_RD and writes it back disabled with
+// RTC_WKALM_SET, since the kernel rejects a request with an invalid time even
+// when it disables the alarm. If the driver does not support the wake alarm
+// ioctls, it is cancelled with CancelSysfsWakeAlarm instead.
 func (c *RTC) CancelWakeAlarm() (err error) {
-	a := &unix.RTCWkAlrm{
-		Enabled: 0,
-		Time:    *timeRtc{Time: time.Time{}}.rtcTime(),
+	wk := new(unix.RTCWkAlrm)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_WKALM_RD, uintptr(unsafe.Pointer(wk))); errno != 0 {
+		if _, ok, err := c.wakeAlarmFallback(errno, time.Time{}); ok {
+			return err
+		}
+		return fmt.Errorf("failed to cancel real-time clock wake alarm: %w", wrapErrno(errno))
 	}
-	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd), unix.RTC_WKALM_SET, uintptr(unsafe.Pointer(a))); errno != 0 {
-		return fmt.Errorf("failed to cancel real-time clock wake alarm: %w", errno)
+	a := disabledWakeAlarm(*wk)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_WKALM_SET, uintptr(unsafe.Pointer(&a))); errno != 0 {
+		if _, ok, err := c.wakeAlarmFallback(errno, time.Time{}); ok {
+			return err
+		}
+		return fmt.Errorf("failed to cancel real-time clock wake alarm: %w", wrapErrno(errno))
 	}
 	return nil
 }
+
+// disabledWakeAlarm returns the RTC_WKALM_SET request that disables the wake
+// alarm wk read with RTC_WKALM_RD, keeping its time.
+func disabledWakeAlarm(wk unix.RTCWkAlrm) unix.RTCWkAlrm {
+	wk.Enabled = 0
+	wk.Pending = 0
+	return wk
+}
+
+// wakeAlarmFallback retries a wake alarm request that RTC_WKALM_SET failed
+// with errno through the wakealarm sysfs attribute, setting t, or cancelling
+// the alarm if t is zero. It reports with ok whether it did, which is only the
+// case for drivers lacking the ioctl: writing the attribute first cancels the
+// current wake alarm, which a request the driver rejected, for example with
+// EINVAL, must not do.
+func (c *RTC) wakeAlarmFallback(errno syscall.Errno, t time.Time) (effective time.Time, ok bool, err error) {
+	if !errors.Is(wrapErrno(errno), ErrNotSupported) {
+		return time.Time{}, false, nil
+	}
+	if t.IsZero() {
+		return time.Time{}, true, c.CancelSysfsWakeAlarm()
+	}
+	effective, err = c.SetSysfsWakeAlarm(t)
+	return effective, true, err
+}
@@ -1,14 +1,18 @@
 package rtc
 
 import (
+	"context"
+	"errors"
 	"math/rand"
-	"strings"
+	"os"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 )
 
 func TestRtcEpoch(t *testing.T) {
@@ -18,7 +22,7 @@ func TestRtcEpoch(t *testing.T) {
 
 	// Read the current epoch value
 	curEpoch, err := c.GetEpoch()
-	if strings.Contains(err.Error(), "inappropriate ioctl for device") {
+	if errors.Is(err, ErrNotSupported) {
 		t.Skipf("Epoch() not supported by this hardware")
 	}
 	require.NoError(t, err)
@@ -85,3 +89,352 @@ func TestRtcFrequency(t *testing.T) {
 	// Restore the original frequency value
 	assert.NoError(t, c.SetFrequency(curFreq))
 }
+
+func TestRtcAlarmResolution(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	res, err := c.AlarmResolution()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, res.Nanoseconds(), time.Second.Nanoseconds())
+
+	// The effective alarm time is rounded to the resolution
+	alarm := time.Now().UTC().Add(time.Hour)
+	effective, err := c.SetAlarm(alarm)
+	require.NoError(t, err)
+	assert.True(t, alarm.Truncate(res).Equal(effective))
+}
+
+func TestRtcMeasureOffset(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	offset, err := c.MeasureOffset(3)
+	require.NoError(t, err)
+
+	// The offset must agree with the one sampled from second changes
+	sample, err := c.SampleTime(3)
+	require.NoError(t, err)
+	assert.InDelta(t, sample.Offset.Seconds(), offset.Seconds(), 0.1)
+}
+
+func TestRtcSyscallConn(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	conn, err := c.SyscallConn()
+	require.NoError(t, err)
+
+	require.NoError(t, c.SetUpdateInterrupt(true))
+	defer c.SetUpdateInterrupt(false)
+
+	// The update interrupt fires once per second
+	buf := make([]byte, 4)
+	var n int
+	var readErr error
+	require.NoError(t, conn.Read(func(fd uintptr) bool {
+		n, readErr = syscall.Read(int(fd), buf)
+		return readErr != syscall.EAGAIN
+	}))
+	require.NoError(t, readErr)
+	assert.Equal(t, 4, n)
+	assert.True(t, parseEvent(buf).Update)
+}
+
+func TestRtcReadOnly(t *testing.T) {
+	c, err := NewRTC("/dev/rtc", WithReadOnly())
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.GetTime()
+	assert.NoError(t, err)
+}
+
+func TestRtcFeatures(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	features, err := c.Features()
+	require.NoError(t, err)
+
+	alarm, err := c.SupportsAlarm()
+	require.NoError(t, err)
+	assert.Equal(t, features.Has(DeviceFeatureAlarm), alarm)
+	if !alarm {
+		wake, err := c.SupportsWakeAlarm()
+		require.NoError(t, err)
+		assert.False(t, wake)
+	}
+}
+
+func TestRtcReadEvent(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	require.NoError(t, c.SetUpdateInterrupt(true))
+	defer c.SetUpdateInterrupt(false)
+
+	e, err := c.ReadEvent()
+	require.NoError(t, err)
+	assert.True(t, e.Update)
+	assert.NotZero(t, e.Count)
+}
+
+func TestRtcWaitForInterrupt(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	// No interrupts are enabled
+	_, err = c.WaitForInterrupt(10 * time.Millisecond)
+	assert.True(t, errors.Is(err, ErrTimeout))
+
+	require.NoError(t, c.SetUpdateInterrupt(true))
+	defer c.SetUpdateInterrupt(false)
+
+	e, err := c.WaitForInterrupt(2 * time.Second)
+	require.NoError(t, err)
+	assert.True(t, e.Update)
+}
+
+func TestRtcWaitForInterruptCtx(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	// No interrupts are enabled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.WaitForInterruptCtx(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	_, err = c.GetTimeCtx(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRtcGetWakeAlarm(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	a, err := c.WakeAlarm()
+	require.NoError(t, err)
+
+	enabled, pending, tm, err := c.GetWakeAlarm()
+	require.NoError(t, err)
+	assert.Equal(t, a.Enabled, enabled)
+	assert.Equal(t, a.Pending, pending)
+	assert.True(t, a.Time.Equal(tm))
+}
+
+func TestRtcSetWakeAlarmEnabled(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+	defer c.CancelWakeAlarm()
+
+	now, err := c.GetTime()
+	require.NoError(t, err)
+
+	effective, err := c.SetWakeAlarmEnabled(now.Add(time.Hour), false)
+	require.NoError(t, err)
+	a, err := c.WakeAlarm()
+	require.NoError(t, err)
+	assert.False(t, a.Enabled)
+	assert.True(t, effective.Equal(a.Time))
+
+	effective, err = c.SetWakeAlarmIn(time.Hour)
+	require.NoError(t, err)
+	a, err = c.WakeAlarm()
+	require.NoError(t, err)
+	assert.True(t, a.Enabled)
+	assert.True(t, effective.Equal(a.Time))
+}
+
+func TestRtcGetTimeSynced(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	rtcTime, edge, err := c.GetTimeSynced()
+	require.NoError(t, err)
+	assert.Less(t, time.Since(edge).Nanoseconds(), time.Second.Nanoseconds())
+
+	now, err := c.GetTime()
+	require.NoError(t, err)
+	assert.InDelta(t, now.Unix(), rtcTime.Unix(), 1)
+}
+
+func TestUntilNextSecond(t *testing.T) {
+	whole := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	assert.Equal(t, int64(0), untilNextSecond(whole).Nanoseconds())
+	assert.Equal(t, (750 * time.Millisecond).Nanoseconds(), untilNextSecond(whole.Add(250*time.Millisecond)).Nanoseconds())
+}
+
+func TestRtcSetTimeSynced(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	require.NoError(t, c.SetTimeSynced(time.Now()))
+
+	offset, err := c.MeasureOffset(3)
+	require.NoError(t, err)
+	assert.InDelta(t, 0, offset.Seconds(), 0.1)
+}
+
+func TestRtcLocalTimeConversion(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	c := &RTC{loc: loc}
+	utc := &RTC{}
+
+	tm := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	raw := c.toRTC(tm)
+	assert.Equal(t, int32(0), raw.Hour)
+	assert.Equal(t, int32(4), raw.Mday)
+	assert.True(t, tm.Equal(c.fromRTC(*raw)))
+
+	// Times in other zones are written in UTC by default
+	raw = utc.toRTC(tm.In(loc))
+	assert.Equal(t, int32(5), raw.Hour)
+	assert.True(t, tm.Equal(utc.fromRTC(*raw)))
+}
+
+func TestRTCTimeConversion(t *testing.T) {
+	tm := time.Date(2024, time.March, 1, 5, 6, 7, 0, time.UTC)
+	raw := ToRTCTime(tm)
+	assert.Equal(t, int32(124), raw.Year)
+	assert.Equal(t, int32(2), raw.Mon)
+	assert.Equal(t, int32(time.Friday), raw.Wday)
+	assert.Equal(t, int32(60), raw.Yday)
+	assert.Equal(t, int32(0), raw.Isdst)
+	assert.True(t, tm.Equal(FromRTCTime(raw)))
+
+	// Times are converted to UTC and truncated to the second
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	assert.True(t, tm.Equal(FromRTCTime(ToRTCTime(tm.In(loc).Add(500*time.Millisecond)))))
+}
+
+func TestDisabledWakeAlarm(t *testing.T) {
+	tm := time.Date(2024, time.March, 1, 5, 6, 7, 0, time.UTC)
+	a := disabledWakeAlarm(unix.RTCWkAlrm{Enabled: 1, Pending: 1, Time: ToRTCTime(tm)})
+	assert.Equal(t, uint8(0), a.Enabled)
+	assert.Equal(t, uint8(0), a.Pending)
+
+	// The kernel validates the time even when disabling the alarm
+	r := a.Time
+	assert.GreaterOrEqual(t, r.Year, int32(70))
+	assert.True(t, r.Mon >= 0 && r.Mon < 12)
+	assert.True(t, r.Mday >= 1 && r.Mday <= 31)
+	assert.True(t, r.Hour >= 0 && r.Hour < 24 && r.Min >= 0 && r.Min < 60 && r.Sec >= 0 && r.Sec < 60)
+	assert.True(t, tm.Equal(FromRTCTime(r)))
+}
+
+func TestRtcNVRAM(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	orig, err := c.ReadNVRAM(0, 4)
+	if errors.Is(err, ErrNotSupported) {
+		t.Skipf("NVRAM not supported by this hardware")
+	}
+	require.NoError(t, err)
+	require.Len(t, orig, 4)
+
+	data := []byte{0xde, 0xad, 0xbe, 0xef}
+	require.NoError(t, c.WriteNVRAM(0, data))
+	defer c.WriteNVRAM(0, orig)
+
+	read, err := c.ReadNVRAM(0, 4)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+}
+
+func TestRtcSetDailyAlarm(t *testing.T) {
+	c, err := NewRTC("/dev/rtc")
+	require.NoError(t, err)
+	defer c.Close()
+
+	assert.Error(t, c.SetDailyAlarm(24, 0, 0))
+
+	require.NoError(t, c.SetDailyAlarm(5, 6, 7))
+	alarm, err := c.GetAlarm()
+	require.NoError(t, err)
+	assert.Equal(t, "05:06", alarm.Format("15:04"))
+
+	now, err := c.GetTime()
+	require.NoError(t, err)
+	_, err = c.SetAlarm(now.Add(25 * time.Hour))
+	var rangeErr *RangeError
+	assert.True(t, errors.As(err, &rangeErr))
+}
+
+func TestRtcCloseTwice(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	c := &RTC{f: f}
+
+	require.NoError(t, c.Close())
+	assert.True(t, errors.Is(c.Close(), ErrClosed))
+	assert.Equal(t, -1, c.fd())
+
+	_, err = c.SyscallConn()
+	assert.True(t, errors.Is(err, ErrClosed))
+	_, err = c.GetTime()
+	assert.True(t, errors.Is(err, ErrClosed))
+}
+
+// TestRtcSyscallConnClose checks that closing the RTC interrupts a raw
+// connection waiting for the device and releases its descriptor.
+func TestRtcSyscallConnClose(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+
+	conn, err := c.SyscallConn()
+	require.NoError(t, err)
+	readErr := make(chan error)
+	go func() {
+		readErr <- conn.Read(func(fd uintptr) bool { return false })
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, c.Close())
+	select {
+	case err := <-readErr:
+		assert.True(t, errors.Is(err, ErrClosed))
+	case <-time.After(time.Second):
+		t.Fatal("Close did not interrupt the raw connection")
+	}
+	assert.Nil(t, c.conn.w, "waiter not closed")
+	assert.True(t, errors.Is(conn.Read(func(fd uintptr) bool { return true }), ErrClosed))
+}
+
+func TestRtcOpenFlags(t *testing.T) {
+	c, err := NewRTC(os.DevNull)
+	require.NoError(t, err)
+	defer c.Close()
+	fdFlags, err := unix.FcntlInt(uintptr(c.fd()), unix.F_GETFD, 0)
+	require.NoError(t, err)
+	assert.NotZero(t, fdFlags&unix.FD_CLOEXEC)
+	flags, err := unix.FcntlInt(uintptr(c.fd()), unix.F_GETFL, 0)
+	require.NoError(t, err)
+	assert.Zero(t, flags&unix.O_NONBLOCK)
+
+	c, err = NewRTC(os.DevNull, WithInheritable(), WithNonBlocking())
+	require.NoError(t, err)
+	defer c.Close()
+	fdFlags, err = unix.FcntlInt(uintptr(c.fd()), unix.F_GETFD, 0)
+	require.NoError(t, err)
+	assert.Zero(t, fdFlags&unix.FD_CLOEXEC)
+	flags, err = unix.FcntlInt(uintptr(c.fd()), unix.F_GETFL, 0)
+	require.NoError(t, err)
+	assert.NotZero(t, flags&unix.O_NONBLOCK)
+}
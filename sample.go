@@ -0,0 +1,133 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// TimeSample is the result of SampleTime.
+type TimeSample struct {
+	// Offset is the median offset of the RTC relative to the system clock.
+	Offset time.Duration
+	// Spread is the difference between the largest and smallest offsets.
+	Spread time.Duration
+	// Samples is the number of second changes that were used.
+	Samples int
+	// Discarded is the number of second changes that were filtered out.
+	Discarded int
+}
+
+const (
+	// samplePoll is how often SampleTime reads the RTC while looking for its
+	// first second change.
+	samplePoll = 5 * time.Millisecond
+	// sampleLead is how long before the expected second change SampleTime
+	// starts reading the RTC back to back.
+	sampleLead = 5 * time.Millisecond
+	// sampleMaxWidth is the widest bracket around a second change that
+	// SampleTime uses, longer ones being delayed by preemption.
+	sampleMaxWidth = 10 * time.Millisecond
+)
+
+// bracketedRead is a read of the RTC time between two reads of the system
+// clock.
+type bracketedRead struct {
+	before, after time.Time
+	rtc           time.Time
+}
+
+func (c *RTC) readBracketed() (r bracketedRead, err error) {
+	r.before = time.Now()
+	if r.rtc, err = c.GetTime(); err != nil {
+		return r, err
+	}
+	r.after = time.Now()
+	return r, nil
+}
+
+// sampleEdge returns the offset of the RTC from two consecutive reads between
+// which it changed second. The new second started after the RTC was read in
+// prev and before it was read in cur, so it is placed halfway between the
+// start of prev and the end of cur. It reports false if the RTC did not step
+// by exactly one second or the reads are too far apart to be accurate.
+func sampleEdge(prev, cur bracketedRead) (offset time.Duration, ok bool) {
+	width := cur.after.Sub(prev.before)
+	if cur.rtc.Sub(prev.rtc) != time.Second || width > sampleMaxWidth {
+		return 0, false
+	}
+	return cur.rtc.Sub(prev.before.Add(width / 2)), true
+}
+
+// SampleTime observes n changes of second of the real-time clock and returns
+// the median offset of the RTC relative to the system clock along with the
+// spread of the samples. Each change is bracketed by the two consecutive reads
+// of the RTC on either side of it, each of those in turn bracketed by reads of
+// the system clock, so the offset is accurate to within the duration of a
+// read rather than the RTC's one second resolution. Changes with a bracket
+// wider than 10ms, for example due to preemption, are discarded.
+// The RTC is read back to back only shortly before each expected change, and
+// sampling takes about n+1 seconds. A positive offset means the RTC is ahead
+// of the system clock.
+func (c *RTC) SampleTime(n int) (sample TimeSample, err error) {
+	if n <= 0 {
+		return sample, errors.New("non-positive sample count for SampleTime")
+	}
+
+	prev, err := c.readBracketed()
+	if err != nil {
+		return sample, err
+	}
+	// The first change only locates the RTC's seconds, polling coarsely.
+	locked := false
+	var next time.Time
+	offsets := make([]time.Duration, 0, n)
+	for len(offsets)+sample.Discarded < n {
+		if !locked {
+			time.Sleep(samplePoll)
+		} else if wait := time.Until(next); wait > 0 {
+			time.Sleep(wait)
+		}
+		cur, err := c.readBracketed()
+		if err != nil {
+			return sample, err
+		}
+		if cur.rtc.Equal(prev.rtc) {
+			prev = cur
+			continue
+		}
+
+		if locked {
+			if offset, ok := sampleEdge(prev, cur); ok {
+				offsets = append(offsets, offset)
+			} else {
+				sample.Discarded++
+			}
+		}
+		locked = true
+		next = prev.before.Add(time.Second - sampleLead)
+		prev = cur
+	}
+	if len(offsets) == 0 {
+		return sample, errors.New("all real-time clock samples were discarded")
+	}
+
+	sample.Offset, sample.Spread = summarizeOffsets(offsets)
+	sample.Samples = len(offsets)
+	return sample, nil
+}
+
+// summarizeOffsets returns the median and spread of the offsets, which must
+// not be empty. The offsets are sorted in place.
+func summarizeOffsets(offsets []time.Duration) (median, spread time.Duration) {
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	mid := len(offsets) / 2
+	median = offsets[mid]
+	if len(offsets)%2 == 0 {
+		median = (offsets[mid-1] + offsets[mid]) / 2
+	}
+	return median, offsets[len(offsets)-1] - offsets[0]
+}
@@ -0,0 +1,49 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeOffsets(t *testing.T) {
+	median, spread := summarizeOffsets([]time.Duration{3, 1, 2})
+	assert.Equal(t, time.Duration(2), median)
+	assert.Equal(t, time.Duration(2), spread)
+
+	median, spread = summarizeOffsets([]time.Duration{4, 1, 2, 3})
+	assert.Equal(t, time.Duration(2), median)
+	assert.Equal(t, time.Duration(3), spread)
+}
+
+func TestSampleEdge(t *testing.T) {
+	base := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	sys := base.Add(-300 * time.Millisecond)
+	prev := bracketedRead{before: sys, after: sys.Add(time.Millisecond), rtc: base.Add(-time.Second)}
+	cur := bracketedRead{before: sys.Add(2 * time.Millisecond), after: sys.Add(4 * time.Millisecond), rtc: base}
+
+	// The second started 2ms after sys, 300ms ahead of the system clock.
+	offset, ok := sampleEdge(prev, cur)
+	require.True(t, ok)
+	assert.Equal(t, 298*time.Millisecond, offset)
+
+	// Reads too far apart
+	cur.after = sys.Add(50 * time.Millisecond)
+	_, ok = sampleEdge(prev, cur)
+	assert.False(t, ok)
+
+	// The RTC was set meanwhile
+	cur.after = sys.Add(4 * time.Millisecond)
+	cur.rtc = base.Add(time.Hour)
+	_, ok = sampleEdge(prev, cur)
+	assert.False(t, ok)
+}
+
+func TestSampleTime(t *testing.T) {
+	sample, err := SampleTime("/dev/rtc", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sample.Samples+sample.Discarded)
+	assert.Less(t, sample.Spread.Nanoseconds(), (20 * time.Millisecond).Nanoseconds())
+}
@@ -0,0 +1,210 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the occurrences of a recurring alarm.
+type Schedule interface {
+	// Next returns the first occurrence after the given time.
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule recurs at a fixed interval.
+type intervalSchedule struct {
+	d time.Duration
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.d)
+}
+
+// clockSchedule recurs at a wall clock time every day, or on one day of the
+// week if weekly is set.
+type clockSchedule struct {
+	clock   time.Time
+	loc     *time.Location
+	weekly  bool
+	weekday time.Weekday
+}
+
+func (s clockSchedule) Next(after time.Time) time.Time {
+	after = after.In(s.loc)
+	for day := 0; ; day++ {
+		t := clockTimeOn(after.Year(), after.Month(), after.Day()+day, s.clock, s.loc)
+		if t.After(after) && (!s.weekly || t.Weekday() == s.weekday) {
+			return t
+		}
+	}
+}
+
+// ParseSchedule parses a recurring schedule such as "every 6 hours",
+// "every minute", "every day at 03:00" or "every monday at 07:30:00".
+// Intervals are given in seconds, minutes, hours or days, and recur from the
+// previous occurrence. Wall clock times are in loc, or in the local time zone
+// if loc is nil.
+func ParseSchedule(spec string, loc *time.Location) (Schedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) < 2 || fields[0] != "every" {
+		return nil, fmt.Errorf("invalid schedule %q, expected \"every ...\"", spec)
+	}
+	fields = fields[1:]
+
+	if len(fields) == 3 && fields[1] == "at" {
+		s := clockSchedule{loc: loc}
+		if fields[0] != "day" {
+			weekday, ok := parseWeekday(fields[0])
+			if !ok {
+				return nil, fmt.Errorf("invalid schedule %q, unknown day %q", spec, fields[0])
+			}
+			s.weekly, s.weekday = true, weekday
+		}
+		var err error
+		if s.clock, err = parseClock(fields[2]); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		return s, nil
+	}
+
+	n := 1
+	if len(fields) == 2 {
+		var err error
+		if n, err = strconv.Atoi(fields[0]); err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q, bad count %q", spec, fields[0])
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("invalid schedule %q", spec)
+	}
+	unit, ok := map[string]time.Duration{
+		"second": time.Second,
+		"minute": time.Minute,
+		"hour":   time.Hour,
+		"day":    24 * time.Hour,
+	}[strings.TrimSuffix(fields[0], "s")]
+	if !ok {
+		return nil, fmt.Errorf("invalid schedule %q, unknown unit %q", spec, fields[0])
+	}
+	return intervalSchedule{d: time.Duration(n) * unit}, nil
+}
+
+// parseClock parses a wall clock time given as "15:04" or "15:04:05".
+func parseClock(clock string) (c time.Time, err error) {
+	if c, err = time.Parse("15:04:05", clock); err == nil {
+		return c, nil
+	}
+	if c, err = time.Parse("15:04", clock); err == nil {
+		return c, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid clock time %q, expected HH:MM or HH:MM:SS", clock)
+}
+
+// parseWeekday parses the English name of a day of the week.
+func parseWeekday(name string) (d time.Weekday, ok bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == name {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Occurrence is delivered by a Scheduler each time its alarm fires.
+type Occurrence struct {
+	// Scheduled is the time the occurrence was scheduled for.
+	Scheduled time.Time
+	Alarm
+}
+
+// Scheduler fires a recurring alarm. It programs the RTC alarm for the next
+// occurrence of its Schedule with a Timer, and re-arms it after each one.
+type Scheduler struct {
+	// C receives the occurrences. If the client falls behind, occurrences are
+	// dropped until it catches up.
+	C <-chan Occurrence
+	// Err receives the error that stopped the Scheduler.
+	Err <-chan error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewScheduler starts a Scheduler for the recurring schedule spec, in the
+// syntax of ParseSchedule with wall clock times in the local time zone. The
+// options configure the Timer armed for each occurrence.
+func NewScheduler(dev string, spec string, opts ...TimerOption) (*Scheduler, error) {
+	schedule, err := ParseSchedule(spec, nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewSchedulerFor(dev, schedule, opts...)
+}
+
+// NewSchedulerFor starts a Scheduler for the given Schedule. Occurrences that
+// have passed by the time the alarm is re-armed, for example while the system
+// was suspended, are skipped.
+func NewSchedulerFor(dev string, schedule Schedule, opts ...TimerOption) (*Scheduler, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	next := schedule.Next(time.Now())
+	timer, err := NewTimerAtCtx(ctx, dev, next, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ch := make(chan Occurrence, 1)
+	errc := make(chan error, 1)
+	s := &Scheduler{
+		C:      ch,
+		Err:    errc,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-timer.Err:
+				errc <- err
+				return
+			case alarm := <-timer.C:
+				select {
+				case ch <- Occurrence{Scheduled: next, Alarm: alarm}:
+				default:
+				}
+			}
+
+			// Skip the occurrences missed while the system was suspended.
+			next = schedule.Next(next)
+			for now := time.Now(); !next.After(now); {
+				next = schedule.Next(next)
+			}
+			if timer, err = NewTimerAtCtx(ctx, dev, next, opts...); err != nil {
+				if ctx.Err() == nil {
+					errc <- err
+				}
+				return
+			}
+		}
+	}()
+	return s, nil
+}
+
+// Stop stops the Scheduler and disarms its alarm. It does not close C.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.done
+}
@@ -0,0 +1,53 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	// A Monday, 12:00 in loc
+	now := time.Date(2030, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	for spec, want := range map[string]time.Time{
+		"every 6 hours":            now.Add(6 * time.Hour),
+		"every minute":             now.Add(time.Minute),
+		"Every 2 Days":             now.Add(48 * time.Hour),
+		"every day at 13:30":       time.Date(2030, time.March, 4, 11, 30, 0, 0, time.UTC),
+		"every day at 03:00":       time.Date(2030, time.March, 5, 1, 0, 0, 0, time.UTC),
+		"every monday at 07:30":    time.Date(2030, time.March, 11, 5, 30, 0, 0, time.UTC),
+		"every friday at 23:59:05": time.Date(2030, time.March, 8, 21, 59, 5, 0, time.UTC),
+	} {
+		s, err := ParseSchedule(spec, loc)
+		require.NoError(t, err, spec)
+		assert.True(t, want.Equal(s.Next(now)), "%s: got %v", spec, s.Next(now))
+	}
+
+	for _, spec := range []string{"", "daily", "every", "every 0 hours", "every fortnight", "every day at noon", "every someday at 03:00"} {
+		_, err := ParseSchedule(spec, loc)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	s, err := NewScheduler("/dev/rtc", "every 2 seconds")
+	require.NoError(t, err)
+	defer s.Stop()
+
+	var last time.Time
+	for i := 0; i < 2; i++ {
+		select {
+		case o := <-s.C:
+			assert.True(t, o.Scheduled.After(last))
+			last = o.Scheduled
+		case err := <-s.Err:
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("scheduler did not fire in time")
+		}
+	}
+}
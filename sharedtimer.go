@@ -0,0 +1,291 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// timerMux multiplexes timers onto the single standard alarm of a shared
+// device. It keeps the pending expirations in a min-heap, programs the
+// hardware with the earliest one, and delivers an Alarm to every timer that
+// is due when the alarm fires.
+type timerMux struct {
+	d *sharedDevice
+
+	mu      sync.Mutex
+	pending muxHeap
+	// alarm is the effective time of the armed alarm, and target the time it
+	// was armed for. Both are zero while no alarm is armed.
+	alarm, target time.Time
+	// holding is set while the mux holds a reference on the alarm
+	// interrupt.
+	holding bool
+}
+
+// muxEntry is a pending expiration of a SharedTimer.
+type muxEntry struct {
+	at      time.Time
+	ch      chan Alarm
+	errc    chan error
+	owner   *SharedRTC
+	started suspendClock
+	index   int
+}
+
+// muxHeap orders entries by expiry time. It implements heap.Interface.
+type muxHeap []*muxEntry
+
+func (h muxHeap) Len() int           { return len(h) }
+func (h muxHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+
+func (h muxHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *muxHeap) Push(x interface{}) {
+	e := x.(*muxEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *muxHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
+
+// SharedTimer is a timer scheduled on a device shared through a Manager.
+// Any number of SharedTimers can be pending on the same device; they share
+// its standard alarm, which is always armed for the earliest of them.
+type SharedTimer struct {
+	C <-chan Alarm
+	// Err receives the error that ended the SharedTimer without firing, when
+	// the alarm could not be re-armed for it.
+	Err <-chan error
+
+	s *timerMux
+	e *muxEntry
+}
+
+// NewTimerAt creates a SharedTimer that sends an Alarm on its channel once
+// the RTC reaches t, rounded up to the next whole second. Times beyond the
+// 24 hour reach of the standard alarm are reached with intermediate alarms.
+// The alarms are programmed through the Manager, so the handles of a device
+// must not arm the standard alarm with SetStandardAlarm while SharedTimers
+// are pending on it. Pending timers are stopped when the handle that created
+// them is closed. If t is outside the range the device can represent, a
+// *RangeError is returned.
+func (s *SharedRTC) NewTimerAt(t time.Time) (*SharedTimer, error) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil, fmt.Errorf("failed to create timer: %w", ErrClosed)
+	}
+	if err := s.d.rtc.checkRange(t); err != nil {
+		return nil, err
+	}
+	if sec := t.Truncate(time.Second); !sec.Equal(t) {
+		t = sec.Add(time.Second)
+	}
+	e := &muxEntry{
+		at:      t,
+		ch:      make(chan Alarm, 1),
+		errc:    make(chan error, 1),
+		owner:   s,
+		started: readSuspendClock(),
+	}
+	mux := s.d.timerMux(s)
+
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	heap.Push(&mux.pending, e)
+	if err := mux.update(Event{}); err != nil {
+		if e.index >= 0 {
+			heap.Remove(&mux.pending, e.index)
+		}
+		// Re-arm for the timers already pending.
+		mux.refresh(Event{})
+		return nil, err
+	}
+	return &SharedTimer{C: e.ch, Err: e.errc, s: mux, e: e}, nil
+}
+
+// NewTimer creates a SharedTimer that sends an Alarm on its channel after d
+// has elapsed on the RTC. See NewTimerAt.
+func (s *SharedRTC) NewTimer(d time.Duration) (*SharedTimer, error) {
+	now, err := s.d.rtc.GetTime()
+	if err != nil {
+		return nil, err
+	}
+	return s.NewTimerAt(now.Add(d))
+}
+
+// Stop prevents the SharedTimer from firing.
+// It returns true if the call stops the timer, false if the timer has already
+// expired or been stopped.
+func (t *SharedTimer) Stop() bool {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+	if t.e.index < 0 {
+		return false
+	}
+	first := t.e.index == 0
+	heap.Remove(&t.s.pending, t.e.index)
+	if first {
+		// Re-arm for the next timer, or release the alarm interrupt.
+		t.s.refresh(Event{})
+	}
+	return true
+}
+
+// timerMux returns the device's timer mux, starting it on first use with a
+// subscription made through s.
+func (d *sharedDevice) timerMux(s *SharedRTC) *timerMux {
+	d.muxOnce.Do(func() {
+		mux := &timerMux{d: d}
+		events, _ := s.SubscribeAlarm(AlarmStandard)
+		d.wait.Add(1)
+		go mux.run(events)
+
+		d.mu.Lock()
+		d.mux = mux
+		d.mu.Unlock()
+	})
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mux
+}
+
+// run updates the mux on every alarm event until the subscription is
+// closed with the device.
+func (s *timerMux) run(events <-chan Event) {
+	defer s.d.wait.Done()
+	for e := range events {
+		s.mu.Lock()
+		s.refresh(e)
+		s.mu.Unlock()
+	}
+}
+
+// removeOwner stops the pending timers created through the handle owner.
+func (s *timerMux) removeOwner(owner *SharedRTC) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := false
+	for i := 0; i < len(s.pending); {
+		if s.pending[i].owner == owner {
+			heap.Remove(&s.pending, i)
+			removed = true
+			continue
+		}
+		i++
+	}
+	if removed {
+		s.refresh(Event{})
+	}
+}
+
+// refresh updates the mux for the event e. If that fails, the pending timers
+// would never fire, so they are ended with the error on their Err channel.
+// The caller must hold s.mu.
+func (s *timerMux) refresh(e Event) {
+	err := s.update(e)
+	if err == nil {
+		return
+	}
+	for len(s.pending) > 0 {
+		entry := heap.Pop(&s.pending).(*muxEntry)
+		entry.errc <- fmt.Errorf("failed to arm shared timer alarm: %w", err)
+	}
+	// Release the alarm interrupt.
+	_ = s.update(Event{})
+}
+
+// update delivers an Alarm to every due timer and arms the alarm for the
+// earliest pending one, releasing the alarm interrupt once none are left.
+// A timer is due once the RTC reaches its time, or once the alarm armed for
+// it has been reached, since devices with a coarse alarm resolution may fire
+// ahead of it. The caller must hold s.mu.
+func (s *timerMux) update(e Event) (err error) {
+	for {
+		now, err := s.d.rtc.GetTime()
+		if err != nil {
+			return err
+		}
+		limit := now
+		if !s.alarm.IsZero() && !s.alarm.After(now) && s.target.After(now) {
+			limit = s.target
+		}
+		s.fire(limit, now, e)
+
+		if len(s.pending) == 0 {
+			s.alarm, s.target = time.Time{}, time.Time{}
+			if s.holding {
+				if err := s.d.setInterrupt(InterruptAlarm, false); err != nil {
+					return err
+				}
+				s.holding = false
+			}
+			return nil
+		}
+
+		target := s.pending[0].at
+		if max := now.Add(maxAlarmHop); target.After(max) {
+			target = max
+		}
+		if !target.Equal(s.target) || !s.alarm.After(now) {
+			s.d.mu.Lock()
+			alarm, err := s.d.rtc.SetAlarm(target)
+			if err == nil {
+				s.d.armed = AlarmStandard
+			}
+			s.d.mu.Unlock()
+			if err != nil {
+				return err
+			}
+			s.alarm, s.target = alarm, target
+		}
+		if !s.holding {
+			if err := s.d.setInterrupt(InterruptAlarm, true); err != nil {
+				return err
+			}
+			s.holding = true
+		}
+
+		// The alarm may not fire if the RTC has already reached it.
+		if now, err = s.d.rtc.GetTime(); err != nil {
+			return err
+		}
+		if s.alarm.After(now) {
+			return nil
+		}
+	}
+}
+
+// fire removes the timers due by limit and delivers their Alarm, reporting
+// now as the RTC time and e as the interrupt that fired them.
+func (s *timerMux) fire(limit, now time.Time, e Event) {
+	for len(s.pending) > 0 && !s.pending[0].at.After(limit) {
+		entry := heap.Pop(&s.pending).(*muxEntry)
+		select {
+		case entry.ch <- Alarm{
+			Time:      time.Now(),
+			RTCTime:   now,
+			Event:     e,
+			Suspended: readSuspendClock().suspendedSince(entry.started),
+		}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package rtc
+
+import (
+	"container/heap"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuxHeap(t *testing.T) {
+	base := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	var h muxHeap
+	entries := make([]*muxEntry, 5)
+	for i, offset := range []int{3, 1, 4, 0, 2} {
+		entries[i] = &muxEntry{at: base.Add(time.Duration(offset) * time.Second)}
+		heap.Push(&h, entries[i])
+	}
+
+	// Removing an entry keeps the remaining ones in order
+	heap.Remove(&h, entries[2].index)
+	assert.Equal(t, -1, entries[2].index)
+
+	var got []time.Time
+	for h.Len() > 0 {
+		got = append(got, heap.Pop(&h).(*muxEntry).at)
+	}
+	assert.Equal(t, []time.Time{base, base.Add(time.Second), base.Add(2 * time.Second), base.Add(3 * time.Second)}, got)
+}
+
+func TestSharedTimers(t *testing.T) {
+	m := &Manager{}
+	a, err := m.Open("/dev/rtc")
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := m.Open("/dev/rtc")
+	require.NoError(t, err)
+	defer b.Close()
+
+	later, err := a.NewTimer(3 * time.Second)
+	require.NoError(t, err)
+	defer later.Stop()
+	sooner, err := b.NewTimer(time.Second)
+	require.NoError(t, err)
+	defer sooner.Stop()
+	stopped, err := b.NewTimer(2 * time.Second)
+	require.NoError(t, err)
+	assert.True(t, stopped.Stop())
+
+	// Both timers fire, in order, although the device has a single alarm
+	for _, timer := range []*SharedTimer{sooner, later} {
+		select {
+		case alarm := <-timer.C:
+			assert.False(t, alarm.RTCTime.IsZero())
+		case <-time.After(5 * time.Second):
+			t.Fatal("shared timer did not fire in time")
+		}
+	}
+	assert.False(t, sooner.Stop())
+	select {
+	case <-stopped.C:
+		t.Error("stopped timer fired")
+	default:
+	}
+}
+
+// TestMuxRefreshFailure checks that pending timers are ended with an error
+// when their alarm cannot be re-armed.
+func TestMuxRefreshFailure(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	c := &RTC{f: f}
+	require.NoError(t, c.Close())
+
+	mux := &timerMux{d: &sharedDevice{rtc: c}}
+	var entries []*muxEntry
+	for i := 0; i < 2; i++ {
+		e := &muxEntry{at: time.Now().Add(time.Hour), errc: make(chan error, 1)}
+		heap.Push(&mux.pending, e)
+		entries = append(entries, e)
+	}
+	mux.refresh(Event{})
+
+	assert.Zero(t, mux.pending.Len())
+	for _, e := range entries {
+		select {
+		case err := <-e.errc:
+			assert.True(t, errors.Is(err, ErrClosed))
+		default:
+			t.Error("pending timer not ended")
+		}
+	}
+}
+
+func TestSharedTimerClosedHandle(t *testing.T) {
+	s := &SharedRTC{d: &sharedDevice{}, closed: true}
+	_, err := s.NewTimerAt(time.Now().Add(time.Minute))
+	assert.True(t, errors.Is(err, ErrClosed))
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import "time"
+
+// Sleep pauses the calling goroutine for at least duration d using the
+// real-time clock's wake alarm instead of the monotonic clock, which stops
+// while the system is suspended. The wait therefore completes on schedule
+// even if the machine suspends in the middle of it, and the wake alarm
+// resumes a suspended machine when it expires.
+// It waits with a Timer created by NewWakeTimerAt, so an alarm missed across
+// suspend still ends the wait on resume, and the alarm is rounded up on
+// devices with coarse alarm resolution. On devices without RTC_WKALM_SET, a
+// *RangeError is returned if d is more than 24 hours.
+func Sleep(dev string, d time.Duration) (err error) {
+	// The monotonic reading is stripped so that the deadline is compared on
+	// the wall clock, which is corrected from the RTC on resume.
+	deadline := time.Now().Round(0).Add(d)
+
+	timer, err := NewWakeTimerAt(dev, deadline,
+		WithReferenceClock(ReferenceSystem),
+		WithAlarmRounding(AlarmRoundUp),
+		WithPastAlarmPolicy(PastAlarmFireNow))
+	if err != nil {
+		return err
+	}
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case err := <-timer.Err:
+		return err
+	}
+
+	// The RTC's seconds are not aligned with the system clock's.
+	time.Sleep(deadline.Sub(time.Now().Round(0)))
+	return nil
+}
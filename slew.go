@@ -0,0 +1,56 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// adjOffsetSingleshot is ADJ_OFFSET_SINGLESHOT, the adjtime(3) mode of
+// adjtimex(2). It is not defined by golang.org/x/sys/unix.
+const adjOffsetSingleshot = 0x8001
+
+// SlewSystemToRTC gradually adjusts the system clock toward the time of the
+// specified real-time clock device instead of stepping it, for services that
+// cannot tolerate the system clock jumping backwards.
+// The adjustment is handed to the kernel with adjtimex(2), which slews the
+// clock at a bounded rate of 500 µs per second until the offset is absorbed.
+// The offset is measured on the RTC's update interrupt where available, and
+// otherwise assumes the RTC is halfway through its current second.
+// If maxOffset is positive and the offset exceeds it, the system clock is left
+// untouched and an error is returned. The measured offset of the RTC relative
+// to the system clock is returned in either case.
+func SlewSystemToRTC(dev string, maxOffset time.Duration) (offset time.Duration, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+
+	rtcTime, sysTime, err := c.GetTimeSynced()
+	if err != nil {
+		if rtcTime, err = c.GetTime(); err != nil {
+			return 0, err
+		}
+		sysTime = time.Now()
+		rtcTime = rtcTime.Add(time.Second / 2)
+	}
+	offset = rtcTime.Sub(sysTime)
+
+	if maxOffset > 0 && (offset > maxOffset || offset < -maxOffset) {
+		return offset, fmt.Errorf("system clock offset %v from real-time clock exceeds %v", offset, maxOffset)
+	}
+
+	tx := &unix.Timex{
+		Modes:  adjOffsetSingleshot,
+		Offset: clong(offset / time.Microsecond),
+	}
+	if _, err := unix.Adjtimex(tx); err != nil {
+		return offset, fmt.Errorf("failed to slew system clock: %w", err)
+	}
+	return offset, nil
+}
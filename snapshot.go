@@ -0,0 +1,86 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"time"
+)
+
+// Snapshot is the state of a real-time clock captured by Snapshot and applied
+// by Restore. It can be serialized, for example with encoding/json, to restore
+// the state from another process.
+type Snapshot struct {
+	// Taken is the system time at which the snapshot was taken.
+	Taken time.Time `json:"taken"`
+	// Time is the RTC time when the snapshot was taken.
+	Time time.Time `json:"time"`
+	// Frequency is the periodic interrupt frequency, or 0 if the device does
+	// not support periodic interrupts.
+	Frequency uint `json:"frequency,omitempty"`
+	// Alarm is the standard alarm time.
+	Alarm time.Time `json:"alarm"`
+	// WakeAlarm is the wake alarm state. Its enabled flag is also the state of
+	// the alarm interrupt.
+	WakeAlarm WakeAlarm `json:"wake_alarm"`
+	// UpdateInterrupt and PeriodicInterrupt are the interrupt enable states.
+	// They are only known for the device reported in /proc/driver/rtc and
+	// are nil otherwise.
+	UpdateInterrupt   *bool `json:"update_interrupt,omitempty"`
+	PeriodicInterrupt *bool `json:"periodic_interrupt,omitempty"`
+}
+
+// Snapshot captures the state of the real-time clock, so that it can be
+// restored with Restore after tests or maintenance.
+func (c *RTC) Snapshot() (s Snapshot, err error) {
+	if s.Time, err = c.GetTime(); err != nil {
+		return Snapshot{}, err
+	}
+	s.Taken = time.Now()
+	if ok, err := c.SupportsPeriodic(); err != nil {
+		return Snapshot{}, err
+	} else if ok {
+		if s.Frequency, err = c.GetFrequency(); err != nil {
+			return Snapshot{}, err
+		}
+	}
+	if s.Alarm, err = c.GetAlarm(); err != nil {
+		return Snapshot{}, err
+	}
+	if s.WakeAlarm, err = c.WakeAlarm(); err != nil {
+		return Snapshot{}, err
+	}
+	s.UpdateInterrupt, s.PeriodicInterrupt = c.procInterrupts()
+	return s, nil
+}
+
+// Restore applies a state captured by Snapshot to the real-time clock with
+// Configure, rolling back on partial failure. The RTC time is advanced by the
+// time elapsed since the snapshot was taken.
+func (c *RTC) Restore(s Snapshot) (err error) {
+	return c.Configure(s.config(time.Now()))
+}
+
+// config returns the Config restoring the snapshot at the given system time.
+func (s Snapshot) config(now time.Time) (cfg Config) {
+	cfg = Config{
+		TimeSource:        TimeSourceExplicit,
+		Time:              s.Time.Add(now.Sub(s.Taken)),
+		Frequency:         s.Frequency,
+		AlarmInterrupt:    &s.WakeAlarm.Enabled,
+		UpdateInterrupt:   s.UpdateInterrupt,
+		PeriodicInterrupt: s.PeriodicInterrupt,
+	}
+	// The time of a disabled wake alarm is meaningless, so it is cancelled.
+	// An enabled wake alarm is the armed hardware alarm, which the standard
+	// alarm reads back with its full date, possibly beyond the 24 hour reach
+	// of SetAlarm, so only the wake alarm is restored.
+	var wake time.Time
+	if s.WakeAlarm.Enabled {
+		wake = s.WakeAlarm.Time
+	} else {
+		cfg.Alarm = &s.Alarm
+	}
+	cfg.WakeAlarm = &wake
+	return cfg
+}
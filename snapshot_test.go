@@ -0,0 +1,57 @@
+package rtc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotConfig(t *testing.T) {
+	taken := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	on := true
+	s := Snapshot{
+		Taken:           taken,
+		Time:            taken.Add(time.Hour),
+		Frequency:       64,
+		Alarm:           taken.Add(time.Minute),
+		WakeAlarm:       WakeAlarm{Time: taken.Add(time.Minute)},
+		UpdateInterrupt: &on,
+	}
+
+	// Snapshots survive serialization
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	var decoded Snapshot
+	require.NoError(t, json.Unmarshal(b, &decoded))
+
+	cfg := decoded.config(taken.Add(10 * time.Second))
+	assert.Equal(t, TimeSourceExplicit, cfg.TimeSource)
+	assert.True(t, taken.Add(time.Hour+10*time.Second).Equal(cfg.Time))
+	assert.Equal(t, uint(64), cfg.Frequency)
+	assert.True(t, s.Alarm.Equal(*cfg.Alarm))
+	assert.False(t, *cfg.AlarmInterrupt)
+	// The disabled wake alarm is cancelled
+	assert.True(t, cfg.WakeAlarm.IsZero())
+	assert.True(t, *cfg.UpdateInterrupt)
+	assert.Nil(t, cfg.PeriodicInterrupt)
+}
+
+func TestSnapshotConfigWakeAlarm(t *testing.T) {
+	taken := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+	// The standard alarm reads back the full date of the armed wake alarm
+	wake := taken.Add(48 * time.Hour)
+	s := Snapshot{
+		Taken:     taken,
+		Time:      taken,
+		Alarm:     wake,
+		WakeAlarm: WakeAlarm{Enabled: true, Time: wake},
+	}
+
+	cfg := s.config(taken)
+	assert.Nil(t, cfg.Alarm, "standard alarm restored beyond the reach of SetAlarm")
+	assert.True(t, wake.Equal(*cfg.WakeAlarm))
+	assert.True(t, *cfg.AlarmInterrupt)
+}
@@ -0,0 +1,184 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	ntpPacketSize = 48
+	ntpModeClient = 3
+	ntpModeServer = 4
+	// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and the Unix epoch (1970).
+	ntpEpochOffset = 2208988800
+)
+
+// defaultSNTPResync is how often SNTPServer re-reads the RTC by default.
+const defaultSNTPResync = time.Hour
+
+// SNTPServer answers SNTP (RFC 4330) queries using a real-time clock as the
+// time source, so that an isolated network with one RTC equipped node can
+// distribute time without running a full NTP daemon.
+// The RTC is read on its update interrupt for sub-second accuracy, and the
+// time is extrapolated with the system's monotonic clock between reads.
+type SNTPServer struct {
+	// Dev is the real-time clock device.
+	Dev string
+	// Drift is the RTC's frequency error in parts per million, positive when
+	// the RTC runs fast. It is used with Calibrated to correct the time.
+	Drift float64
+	// Calibrated is when the RTC was last known to be correct. If zero, no
+	// drift correction is applied.
+	Calibrated time.Time
+	// Resync is how often the RTC is re-read. Defaults to one hour.
+	Resync time.Duration
+
+	mu     sync.Mutex
+	ref    time.Time
+	refSys time.Time
+}
+
+// ListenAndServe listens for SNTP queries on the UDP address addr, such as
+// ":123", and serves them until the context is done.
+func (s *SNTPServer) ListenAndServe(ctx context.Context, addr string) (err error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return s.Serve(ctx, conn)
+}
+
+// Serve answers SNTP queries received on conn until the context is done.
+func (s *SNTPServer) Serve(ctx context.Context, conn net.PacketConn) (err error) {
+	if err := s.sync(); err != nil {
+		return err
+	}
+
+	resync := s.Resync
+	if resync <= 0 {
+		resync = defaultSNTPResync
+	}
+	ticker := time.NewTicker(resync)
+	defer ticker.Stop()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				_ = conn.SetReadDeadline(time.Now())
+				return
+			case <-ticker.C:
+				// Keep serving from the previous reference if the RTC can't be read.
+				_ = s.sync()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return serveSNTP(ctx, conn, s.now, s.reference)
+}
+
+// sync reads the RTC and records it as the reference for extrapolation.
+func (s *SNTPServer) sync() (err error) {
+	c, err := NewRTC(s.Dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	t, sys, err := c.GetTimeSynced()
+	if err != nil {
+		return err
+	}
+
+	if !s.Calibrated.IsZero() {
+		elapsed := t.Sub(s.Calibrated)
+		t = t.Add(-time.Duration(float64(elapsed) * s.Drift / 1e6))
+	}
+
+	s.mu.Lock()
+	s.ref = t
+	s.refSys = sys
+	s.mu.Unlock()
+	return nil
+}
+
+// now returns the current time extrapolated from the last RTC reference.
+func (s *SNTPServer) now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ref.Add(time.Since(s.refSys))
+}
+
+func (s *SNTPServer) reference() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ref
+}
+
+// serveSNTP answers SNTP client requests on conn using now as the time source
+// until the context is done.
+func serveSNTP(ctx context.Context, conn net.PacketConn, now func() time.Time, reference func() time.Time) (err error) {
+	req := make([]byte, ntpPacketSize)
+	resp := make([]byte, ntpPacketSize)
+	for {
+		n, addr, err := conn.ReadFrom(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		received := now()
+
+		if n < ntpPacketSize || req[0]&0x07 != ntpModeClient {
+			continue
+		}
+
+		buildSNTPResponse(resp, req, reference(), received)
+		binary.BigEndian.PutUint64(resp[40:], ntpTimestamp(now()))
+		_, _ = conn.WriteTo(resp, addr)
+	}
+}
+
+// buildSNTPResponse fills in a server response to req, except for the
+// transmit timestamp.
+func buildSNTPResponse(resp, req []byte, reference, received time.Time) {
+	for i := range resp {
+		resp[i] = 0
+	}
+	version := (req[0] >> 3) & 0x07
+	resp[0] = version<<3 | ntpModeServer
+	resp[1] = 1                                     // stratum: primary reference
+	resp[2] = req[2]                                // poll interval
+	resp[3] = 0xF6                                  // precision: 2^-10 s
+	binary.BigEndian.PutUint32(resp[8:], 1<<16/100) // root dispersion: 10 ms
+	copy(resp[12:16], "RTC")
+	binary.BigEndian.PutUint64(resp[16:], ntpTimestamp(reference))
+	copy(resp[24:32], req[40:48]) // originate timestamp: client's transmit timestamp
+	binary.BigEndian.PutUint64(resp[32:], ntpTimestamp(received))
+}
+
+// ntpTimestamp converts t to the 64-bit NTP timestamp format.
+func ntpTimestamp(t time.Time) uint64 {
+	sec := uint64(t.Unix() + ntpEpochOffset)
+	frac := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return sec<<32 | frac
+}
+
+// ntpTime converts a 64-bit NTP timestamp to a time.Time.
+func ntpTime(ts uint64) time.Time {
+	sec := int64(ts>>32) - ntpEpochOffset
+	nsec := (ts & 0xFFFFFFFF) * uint64(time.Second) >> 32
+	return time.Unix(sec, int64(nsec))
+}
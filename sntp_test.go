@@ -0,0 +1,53 @@
+package rtc
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNTPTimestamp(t *testing.T) {
+	tm := time.Date(2030, time.January, 2, 3, 4, 5, 500000000, time.UTC)
+	assert.WithinDuration(t, tm, ntpTime(ntpTimestamp(tm)), time.Nanosecond)
+}
+
+func TestServeSNTP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fixed := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+	now := func() time.Time { return fixed }
+	go func() {
+		_ = serveSNTP(ctx, conn, now, now)
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := make([]byte, ntpPacketSize)
+	req[0] = 4<<3 | ntpModeClient
+	binary.BigEndian.PutUint64(req[40:], 0x0102030405060708)
+	_, err = client.Write(req)
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(time.Second)))
+	resp := make([]byte, ntpPacketSize)
+	n, err := client.Read(resp)
+	require.NoError(t, err)
+	require.Equal(t, ntpPacketSize, n)
+
+	assert.Equal(t, byte(4<<3|ntpModeServer), resp[0])
+	assert.Equal(t, byte(1), resp[1])
+	assert.Equal(t, uint64(0x0102030405060708), binary.BigEndian.Uint64(resp[24:]))
+	assert.True(t, fixed.Equal(ntpTime(binary.BigEndian.Uint64(resp[40:]))))
+}
@@ -0,0 +1,141 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// SoftwareClock is a Clock backed by the system clock (CLOCK_REALTIME) and a
+// timerfd instead of real-time clock hardware. Like an RTC, its time has a
+// resolution of one second.
+type SoftwareClock struct {
+	fd int
+
+	mu    sync.Mutex
+	alarm time.Time
+	armed bool
+}
+
+// NewSoftwareClock creates a SoftwareClock.
+func NewSoftwareClock() (*SoftwareClock, error) {
+	fd, err := unix.TimerfdCreate(unix.CLOCK_REALTIME, unix.TFD_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create timerfd: %w", err)
+	}
+	return &SoftwareClock{
+		fd: fd,
+	}, nil
+}
+
+// Close releases the SoftwareClock's timerfd.
+func (c *SoftwareClock) Close() (err error) {
+	err = unix.Close(c.fd)
+	c.fd = -1
+	return err
+}
+
+// GetTime returns the system time truncated to the second.
+func (c *SoftwareClock) GetTime() (t time.Time, err error) {
+	return time.Now().UTC().Truncate(time.Second), nil
+}
+
+// SetTime sets the system time.
+func (c *SoftwareClock) SetTime(t time.Time) (err error) {
+	tv := unix.NsecToTimeval(t.Truncate(time.Second).UnixNano())
+	if err := unix.Settimeofday(&tv); err != nil {
+		return fmt.Errorf("failed to set system time: %w", err)
+	}
+	return nil
+}
+
+// GetAlarm returns the alarm time.
+func (c *SoftwareClock) GetAlarm() (t time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.alarm, nil
+}
+
+// SetAlarm sets the alarm time, truncated to the second, and returns it.
+// If the alarm is enabled, it is re-armed for the new time.
+func (c *SoftwareClock) SetAlarm(t time.Time) (effective time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.alarm = t.UTC().Truncate(time.Second)
+	if c.armed {
+		if err := c.arm(true); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return c.alarm, nil
+}
+
+// SetAlarmInterrupt enables or disables the alarm.
+func (c *SoftwareClock) SetAlarmInterrupt(enable bool) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.arm(enable); err != nil {
+		return err
+	}
+	c.armed = enable
+	return nil
+}
+
+// arm programs the timerfd for the alarm time, or disarms it.
+func (c *SoftwareClock) arm(enable bool) (err error) {
+	var spec unix.ItimerSpec
+	if enable {
+		if c.alarm.Unix() > 0 {
+			if spec.Value, err = unix.TimeToTimespec(c.alarm); err != nil {
+				return fmt.Errorf("failed to arm timerfd: %w", err)
+			}
+		} else {
+			// A zero value would disarm the timer. Fire as soon as possible instead.
+			spec.Value.Nsec = 1
+		}
+	}
+	if err := unix.TimerfdSettime(c.fd, unix.TFD_TIMER_ABSTIME, &spec, nil); err != nil {
+		return fmt.Errorf("failed to arm timerfd: %w", err)
+	}
+	return nil
+}
+
+// WaitAlarm blocks until the alarm fires.
+func (c *SoftwareClock) WaitAlarm() (err error) {
+	_, err = c.ReadEvent()
+	return err
+}
+
+// ReadEvent blocks until the alarm fires and returns it as an alarm Event.
+// The SoftwareClock has no update or periodic interrupts.
+func (c *SoftwareClock) ReadEvent() (e Event, err error) {
+	// The timerfd expiration count is a host-endian uint64.
+	var count uint64
+	if _, err := unix.Read(c.fd, (*[8]byte)(unsafe.Pointer(&count))[:]); err != nil {
+		return Event{}, fmt.Errorf("failed to read timerfd: %w", err)
+	}
+	return Event{
+		Alarm: true,
+		Count: uint32(count),
+	}, nil
+}
+
+// WaitForInterrupt waits up to timeout for the alarm to fire and returns it.
+// If it does not fire in time, the returned error wraps ErrTimeout. A
+// negative timeout waits indefinitely.
+func (c *SoftwareClock) WaitForInterrupt(timeout time.Duration) (e Event, err error) {
+	readable, err := pollReadable(c.fd, timeout)
+	if err != nil {
+		return Event{}, err
+	}
+	if !readable {
+		return Event{}, fmt.Errorf("%w after %v", ErrTimeout, timeout)
+	}
+	return c.ReadEvent()
+}
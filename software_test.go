@@ -0,0 +1,63 @@
+package rtc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftwareClockAlarm(t *testing.T) {
+	c, err := NewSoftwareClock()
+	require.NoError(t, err)
+	defer c.Close()
+
+	now, err := c.GetTime()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), now, time.Second)
+
+	alarm := now.Add(2 * time.Second)
+	effective, err := c.SetAlarm(alarm)
+	require.NoError(t, err)
+	assert.True(t, alarm.Equal(effective))
+	require.NoError(t, c.SetAlarmInterrupt(true))
+
+	readAlarm, err := c.GetAlarm()
+	require.NoError(t, err)
+	assert.True(t, alarm.Equal(readAlarm))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WaitAlarm()
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+		assert.False(t, time.Now().Before(alarm))
+	case <-time.After(4 * time.Second):
+		t.Error("alarm did not trigger in time")
+	}
+}
+
+func TestSoftwareClockWaitForInterrupt(t *testing.T) {
+	c, err := NewSoftwareClock()
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.WaitForInterrupt(10 * time.Millisecond)
+	assert.True(t, errors.Is(err, ErrTimeout))
+
+	now, err := c.GetTime()
+	require.NoError(t, err)
+	_, err = c.SetAlarm(now.Add(time.Second))
+	require.NoError(t, err)
+	require.NoError(t, c.SetAlarmInterrupt(true))
+
+	e, err := c.WaitForInterrupt(3 * time.Second)
+	require.NoError(t, err)
+	assert.True(t, e.Alarm)
+	assert.Equal(t, uint32(1), e.Count)
+}
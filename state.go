@@ -0,0 +1,122 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeviceState is the full state of a real-time clock returned by State, for
+// monitoring agents to report with a single call. It marshals to JSON with
+// the battery flags as text, and String formats it as one setting per line.
+type DeviceState struct {
+	// Time is the RTC time.
+	Time time.Time
+	// Alarm is the standard alarm time.
+	Alarm time.Time
+	// WakeAlarm is the wake alarm state.
+	WakeAlarm WakeAlarm
+	// Frequency is the periodic interrupt frequency, or 0 if the device does
+	// not support periodic interrupts.
+	Frequency uint
+	// AlarmInterrupt is the alarm interrupt enable state.
+	AlarmInterrupt bool
+	// UpdateInterrupt and PeriodicInterrupt are the interrupt enable states.
+	// They are only known for the device reported in /proc/driver/rtc and
+	// are nil otherwise.
+	UpdateInterrupt   *bool
+	PeriodicInterrupt *bool
+	// Battery holds the voltage low flags, or nil if the device does not
+	// report them.
+	Battery *VoltageLow
+}
+
+// State reads the full state of the real-time clock.
+func (c *RTC) State() (s DeviceState, err error) {
+	snap, err := c.Snapshot()
+	if err != nil {
+		return DeviceState{}, err
+	}
+	s = DeviceState{
+		Time:              snap.Time,
+		Alarm:             snap.Alarm,
+		WakeAlarm:         snap.WakeAlarm,
+		Frequency:         snap.Frequency,
+		AlarmInterrupt:    snap.WakeAlarm.Enabled,
+		UpdateInterrupt:   snap.UpdateInterrupt,
+		PeriodicInterrupt: snap.PeriodicInterrupt,
+	}
+	if v, err := c.GetVoltageLow(); err == nil {
+		s.Battery = &v
+	} else if !isUnsupported(err) {
+		return DeviceState{}, err
+	}
+	return s, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s DeviceState) MarshalJSON() ([]byte, error) {
+	type wakeAlarm struct {
+		Enabled bool      `json:"enabled"`
+		Pending bool      `json:"pending"`
+		Time    time.Time `json:"time"`
+	}
+	v := struct {
+		Time              time.Time `json:"time"`
+		Alarm             time.Time `json:"alarm"`
+		WakeAlarm         wakeAlarm `json:"wake_alarm"`
+		Frequency         uint      `json:"frequency,omitempty"`
+		AlarmInterrupt    bool      `json:"alarm_interrupt"`
+		UpdateInterrupt   *bool     `json:"update_interrupt,omitempty"`
+		PeriodicInterrupt *bool     `json:"periodic_interrupt,omitempty"`
+		Battery           string    `json:"battery,omitempty"`
+	}{
+		Time:              s.Time,
+		Alarm:             s.Alarm,
+		WakeAlarm:         wakeAlarm(s.WakeAlarm),
+		Frequency:         s.Frequency,
+		AlarmInterrupt:    s.AlarmInterrupt,
+		UpdateInterrupt:   s.UpdateInterrupt,
+		PeriodicInterrupt: s.PeriodicInterrupt,
+	}
+	if s.Battery != nil {
+		v.Battery = s.Battery.String()
+	}
+	return json.Marshal(v)
+}
+
+func (s DeviceState) String() string {
+	var b strings.Builder
+	line := func(name string, value interface{}) {
+		fmt.Fprintf(&b, "%-20s: %v\n", name, value)
+	}
+	enabled := func(p *bool) string {
+		switch {
+		case p == nil:
+			return "unknown"
+		case *p:
+			return "yes"
+		default:
+			return "no"
+		}
+	}
+	line("time", s.Time.Format(time.RFC3339))
+	line("alarm", s.Alarm.Format("15:04:05"))
+	line("wake alarm", s.WakeAlarm.Time.Format(time.RFC3339))
+	line("wake alarm enabled", enabled(&s.WakeAlarm.Enabled))
+	line("wake alarm pending", enabled(&s.WakeAlarm.Pending))
+	line("frequency", s.Frequency)
+	line("alarm IRQ enabled", enabled(&s.AlarmInterrupt))
+	line("update IRQ enabled", enabled(s.UpdateInterrupt))
+	line("periodic IRQ enabled", enabled(s.PeriodicInterrupt))
+	battery := "unknown"
+	if s.Battery != nil {
+		battery = s.Battery.String()
+	}
+	line("battery", battery)
+	return b.String()
+}
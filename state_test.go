@@ -0,0 +1,47 @@
+package rtc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceStateMarshalJSON(t *testing.T) {
+	on := true
+	low := VoltageLowBackupLow
+	s := DeviceState{
+		Time:            time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC),
+		Alarm:           time.Date(2030, time.March, 4, 6, 0, 0, 0, time.UTC),
+		WakeAlarm:       WakeAlarm{Enabled: true, Time: time.Date(2030, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		Frequency:       64,
+		AlarmInterrupt:  true,
+		UpdateInterrupt: &on,
+		Battery:         &low,
+	}
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"time": "2030-03-04T05:06:07Z",
+		"alarm": "2030-03-04T06:00:00Z",
+		"wake_alarm": {"enabled": true, "pending": false, "time": "2030-03-05T00:00:00Z"},
+		"frequency": 64,
+		"alarm_interrupt": true,
+		"update_interrupt": true,
+		"battery": "backup low"
+	}`, string(b))
+}
+
+func TestDeviceStateString(t *testing.T) {
+	s := DeviceState{
+		Time:  time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC),
+		Alarm: time.Date(2030, time.March, 4, 6, 0, 0, 0, time.UTC),
+	}
+	out := s.String()
+	assert.Contains(t, out, "time                : 2030-03-04T05:06:07Z\n")
+	assert.Contains(t, out, "alarm               : 06:00:00\n")
+	assert.Contains(t, out, "update IRQ enabled  : unknown\n")
+	assert.Contains(t, out, "battery             : unknown\n")
+}
@@ -7,12 +7,29 @@ import (
 
 // GetClocks returns a list of real-time clocks in the system.
 func GetClocks() (devices []string, err error) {
-	return filepath.Glob("/dev/rtc*")
+	return filepath.Glob(filepath.Join(devDir(), "rtc*"))
+}
+
+// WithRTC opens the specified real-time clock device, calls fn with it and
+// closes it, so that several operations share one open device without manual
+// lifecycle code. The device is closed even if fn panics. The error of fn is
+// returned, or else the error closing the device.
+func WithRTC(dev string, fn func(c *RTC) error) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return fn(c)
 }
 
 // GetEpoch reads the epoch from the specified real-time clock device.
 func GetEpoch(dev string) (epoch uint, err error) {
-	c, err := NewRTC(dev)
+	c, err := NewRTC(dev, WithReadOnly())
 	if err != nil {
 		return 0, err
 	}
@@ -32,7 +49,7 @@ func SetEpoch(dev string, epoch uint) (err error) {
 
 // GetTime reads the time from the specified real-time clock device.
 func GetTime(dev string) (t time.Time, err error) {
-	c, err := NewRTC(dev)
+	c, err := NewRTC(dev, WithReadOnly())
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -40,6 +57,39 @@ func GetTime(dev string) (t time.Time, err error) {
 	return c.GetTime()
 }
 
+// GetTimeSynced reads the time from the specified real-time clock device at
+// the next second edge, and returns it along with the system time of the edge.
+func GetTimeSynced(dev string) (t time.Time, edge time.Time, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	defer c.Close()
+	return c.GetTimeSynced()
+}
+
+// SampleTime observes n changes of second of the specified real-time clock
+// device and returns the median offset of the RTC relative to the system clock.
+func SampleTime(dev string, n int) (sample TimeSample, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return TimeSample{}, err
+	}
+	defer c.Close()
+	return c.SampleTime(n)
+}
+
+// MeasureOffset measures the offset of the specified real-time clock device
+// relative to the system clock with sub-second resolution.
+func MeasureOffset(dev string, edges int) (offset time.Duration, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.MeasureOffset(edges)
+}
+
 // SetTime sets the time for the specified real-time clock device.
 func SetTime(dev string, t time.Time) (err error) {
 	c, err := NewRTC(dev)
@@ -50,9 +100,20 @@ func SetTime(dev string, t time.Time) (err error) {
 	return c.SetTime(t)
 }
 
+// SetTimeSynced sets the time for the specified real-time clock device with
+// sub-second accuracy by writing it on a second boundary.
+func SetTimeSynced(dev string, t time.Time) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.SetTimeSynced(t)
+}
+
 // GetFrequency returns the frequency of the specified real-time clock device.
 func GetFrequency(dev string) (frequency uint, err error) {
-	c, err := NewRTC(dev)
+	c, err := NewRTC(dev, WithReadOnly())
 	if err != nil {
 		return 0, err
 	}
@@ -71,38 +132,80 @@ func SetFrequency(dev string, frequency uint) (err error) {
 }
 
 // SetPeriodicInterrupt enables or disables periodic interrupts for the specified real-time clock device.
+//
+// Compatibility note: earlier releases returned nil when the device could not
+// be opened, so the call silently succeeded on machines without an RTC. The
+// error opening the device is now returned.
 func SetPeriodicInterrupt(dev string, enable bool) (err error) {
 	c, err := NewRTC(dev)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer c.Close()
 	return c.SetPeriodicInterrupt(enable)
 }
 
 // SetAlarmInterrupt enables or disables the alarm interrupt for the specified real-time clock device.
+//
+// Compatibility note: earlier releases returned nil when the device could not
+// be opened, so the call silently succeeded on machines without an RTC. The
+// error opening the device is now returned.
 func SetAlarmInterrupt(dev string, enable bool) (err error) {
 	c, err := NewRTC(dev)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer c.Close()
 	return c.SetAlarmInterrupt(enable)
 }
 
 // SetUpdateInterrupt enables or disables the update interrupt for the specified real-time clock device.
+//
+// Compatibility note: earlier releases returned nil when the device could not
+// be opened, so the call silently succeeded on machines without an RTC. The
+// error opening the device is now returned.
 func SetUpdateInterrupt(dev string, enable bool) (err error) {
 	c, err := NewRTC(dev)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer c.Close()
 	return c.SetUpdateInterrupt(enable)
 }
 
+// GetPeriodicInterrupt reports whether periodic interrupts are enabled for the specified real-time clock device.
+func GetPeriodicInterrupt(dev string) (enabled bool, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return false, err
+	}
+	defer c.Close()
+	return c.GetPeriodicInterrupt()
+}
+
+// GetAlarmInterrupt reports whether the alarm interrupt is enabled for the specified real-time clock device.
+func GetAlarmInterrupt(dev string) (enabled bool, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return false, err
+	}
+	defer c.Close()
+	return c.GetAlarmInterrupt()
+}
+
+// GetUpdateInterrupt reports whether the update interrupt is enabled for the specified real-time clock device.
+func GetUpdateInterrupt(dev string) (enabled bool, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return false, err
+	}
+	defer c.Close()
+	return c.GetUpdateInterrupt()
+}
+
 // GetAlarm returns the alarm time for the specified real-time clock device.
 func GetAlarm(dev string) (t time.Time, err error) {
-	c, err := NewRTC(dev)
+	c, err := NewRTC(dev, WithReadOnly())
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -110,19 +213,22 @@ func GetAlarm(dev string) (t time.Time, err error) {
 	return c.GetAlarm()
 }
 
-// SetAlarm sets the alarm time for the specified real-time clock device.
-func SetAlarm(dev string, t time.Time) (err error) {
+// SetAlarm sets the alarm time for the specified real-time clock device and
+// returns the effective alarm time after rounding to the device's resolution.
+func SetAlarm(dev string, t time.Time) (effective time.Time, err error) {
 	c, err := NewRTC(dev)
 	if err != nil {
-		return err
+		return time.Time{}, err
 	}
 	defer c.Close()
 	return c.SetAlarm(t)
 }
 
 // GetWakeAlarm returns the current state of the wake alarm for the specified real-time clock device.
+//
+// Deprecated: Use ReadWakeAlarm, which returns the state as a struct.
 func GetWakeAlarm(dev string) (enabled bool, pending bool, t time.Time, err error) {
-	c, err := NewRTC(dev)
+	c, err := NewRTC(dev, WithReadOnly())
 	if err != nil {
 		return false, false, time.Time{}, err
 	}
@@ -130,8 +236,19 @@ func GetWakeAlarm(dev string) (enabled bool, pending bool, t time.Time, err erro
 	return c.GetWakeAlarm()
 }
 
-// SetWakeAlarm sets the wake alarm time for the specified real-time clock device.
-func SetWakeAlarm(dev string, t time.Time) (err error) {
+// ReadWakeAlarm returns the state of the wake alarm for the specified real-time clock device.
+func ReadWakeAlarm(dev string) (a WakeAlarm, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return WakeAlarm{}, err
+	}
+	defer c.Close()
+	return c.WakeAlarm()
+}
+
+// SetWakeAlarm sets the wake alarm time for the specified real-time clock device
+// and returns the effective alarm time after rounding to the device's resolution.
+func SetWakeAlarm(dev string, t time.Time) (effective time.Time, err error) {
 	c, err := NewRTC(dev)
 	if err != nil {
 		return
@@ -140,6 +257,28 @@ func SetWakeAlarm(dev string, t time.Time) (err error) {
 	return c.SetWakeAlarm(t)
 }
 
+// SetWakeAlarmEnabled sets the wake alarm time for the specified real-time clock
+// device, armed only if enabled is true, and returns the effective alarm time.
+func SetWakeAlarmEnabled(dev string, t time.Time, enabled bool) (effective time.Time, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer c.Close()
+	return c.SetWakeAlarmEnabled(t, enabled)
+}
+
+// SetWakeAlarmIn sets the wake alarm for the specified real-time clock device
+// to fire after the duration d and returns the effective alarm time.
+func SetWakeAlarmIn(dev string, d time.Duration) (effective time.Time, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer c.Close()
+	return c.SetWakeAlarmIn(d)
+}
+
 // CancelWakeAlarm cancels the wake alarm for the specified real-time clock device.
 func CancelWakeAlarm(dev string) (err error) {
 	c, err := NewRTC(dev)
@@ -149,3 +288,308 @@ func CancelWakeAlarm(dev string) (err error) {
 	defer c.Close()
 	return c.CancelWakeAlarm()
 }
+
+// BootClockError estimates how far off the system clock was when it was
+// initialized from the specified real-time clock device at boot. ntpCorrection
+// is the first correction NTP applied to the system clock, or nil if unknown.
+func BootClockError(dev string, ntpCorrection *time.Duration) (r BootClockReport, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return BootClockReport{}, err
+	}
+	defer c.Close()
+	return c.BootClockError(ntpCorrection)
+}
+
+// LastPowerFailure returns the time of the last power failure recorded by the
+// specified real-time clock device.
+func LastPowerFailure(dev string) (t time.Time, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer c.Close()
+	return c.LastPowerFailure()
+}
+
+// Configure applies the desired state in cfg to the specified real-time clock
+// device, rolling back on partial failure.
+func Configure(dev string, cfg Config) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.Configure(cfg)
+}
+
+// GetVoltageLow returns the voltage low flags of the specified real-time clock
+// device.
+func GetVoltageLow(dev string) (flags VoltageLow, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.GetVoltageLow()
+}
+
+// ClearVoltageLow clears the voltage low flags of the specified real-time
+// clock device.
+func ClearVoltageLow(dev string) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.ClearVoltageLow()
+}
+
+// GetCorrectionPPB returns the frequency correction in parts per billion of the
+// specified real-time clock device.
+func GetCorrectionPPB(dev string) (ppb int64, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.GetCorrectionPPB()
+}
+
+// SetCorrectionPPB sets the frequency correction in parts per billion of the
+// specified real-time clock device.
+func SetCorrectionPPB(dev string, ppb int64) (err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.SetCorrectionPPB(ppb)
+}
+
+// SetDailyAlarm sets the standard alarm of the specified real-time clock device
+// to fire at the given time of day within the next 24 hours.
+func SetDailyAlarm(dev string, hour, min, sec int) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.SetDailyAlarm(hour, min, sec)
+}
+
+// State returns the full state of the specified real-time clock device.
+func State(dev string) (s DeviceState, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return DeviceState{}, err
+	}
+	defer c.Close()
+	return c.State()
+}
+
+// ProcInfo returns the state of the specified real-time clock device reported
+// by /proc/driver/rtc.
+func ProcInfo(dev string) (info ProcDriverInfo, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return ProcDriverInfo{}, err
+	}
+	defer c.Close()
+	return c.ProcInfo()
+}
+
+// Temperature returns the die temperature in degrees Celsius of the specified
+// real-time clock device.
+func Temperature(dev string) (celsius float64, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.Temperature()
+}
+
+// GetBackupSwitchMode returns the backup switch mode of the specified
+// real-time clock device.
+func GetBackupSwitchMode(dev string) (mode BackupSwitchMode, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.GetBackupSwitchMode()
+}
+
+// SetBackupSwitchMode sets the backup switch mode of the specified real-time
+// clock device.
+func SetBackupSwitchMode(dev string, mode BackupSwitchMode) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.SetBackupSwitchMode(mode)
+}
+
+// InterruptCount returns the number of interrupts delivered on the IRQ line of
+// the specified real-time clock device since boot.
+func InterruptCount(dev string) (count uint64, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.InterruptCount()
+}
+
+// Wakeup reports whether the specified real-time clock device can wake the
+// system from sleep, and whether that is enabled.
+func Wakeup(dev string) (capable bool, enabled bool, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return false, false, err
+	}
+	defer c.Close()
+	return c.Wakeup()
+}
+
+// Info returns information identifying the specified real-time clock device.
+func Info(dev string) (info DeviceInfo, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+	defer c.Close()
+	return c.Info()
+}
+
+// Hctosys reports whether the kernel used the specified real-time clock device
+// to initialize the system clock at boot.
+func Hctosys(dev string) (hctosys bool, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return false, err
+	}
+	defer c.Close()
+	return c.Hctosys()
+}
+
+// Features returns the capabilities of the specified real-time clock device.
+func Features(dev string) (features DeviceFeatures, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.Features()
+}
+
+// AlarmResolution returns the alarm resolution of the specified real-time
+// clock device.
+func AlarmResolution(dev string) (resolution time.Duration, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.AlarmResolution()
+}
+
+// GetParamFeatures returns the device features of the specified real-time
+// clock device reported by the RTC_PARAM_FEATURES parameter.
+func GetParamFeatures(dev string) (features DeviceFeatures, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.GetParamFeatures()
+}
+
+// GetCorrection returns the frequency correction in parts per billion of the
+// specified real-time clock device, read from the RTC_PARAM_CORRECTION
+// parameter.
+func GetCorrection(dev string) (ppb int64, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.GetCorrection()
+}
+
+// SetCorrection sets the frequency correction in parts per billion of the
+// specified real-time clock device with the RTC_PARAM_CORRECTION parameter.
+func SetCorrection(dev string, ppb int64) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.SetCorrection(ppb)
+}
+
+// GetFrequencyCorrection returns the aging offset in parts per billion of the
+// specified real-time clock device.
+func GetFrequencyCorrection(dev string) (ppb int64, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.GetFrequencyCorrection()
+}
+
+// SetFrequencyCorrection sets the aging offset in parts per billion of the
+// specified real-time clock device.
+func SetFrequencyCorrection(dev string, ppb int64) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.SetFrequencyCorrection(ppb)
+}
+
+// GetPLL returns the PLL state of the specified real-time clock device.
+func GetPLL(dev string) (info PLLInfo, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return PLLInfo{}, err
+	}
+	defer c.Close()
+	return c.GetPLL()
+}
+
+// SetPLL sets the PLL state of the specified real-time clock device.
+func SetPLL(dev string, info PLLInfo) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.SetPLL(info)
+}
+
+// ReadNVRAM reads n bytes at offset from the battery-backed NVRAM of the
+// specified real-time clock device.
+func ReadNVRAM(dev string, offset int64, n int) (data []byte, err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	return c.ReadNVRAM(offset, n)
+}
+
+// WriteNVRAM writes data at offset to the battery-backed NVRAM of the
+// specified real-time clock device.
+func WriteNVRAM(dev string, offset int64, data []byte) (err error) {
+	c, err := NewRTC(dev, WithReadOnly())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.WriteNVRAM(offset, data)
+}
@@ -1,7 +1,9 @@
 package rtc
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -94,3 +96,29 @@ func TestSetFrequency(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, f, uint(32))
 }
+
+func TestInterruptSettersOpenError(t *testing.T) {
+	dev := filepath.Join(t.TempDir(), "rtc")
+	assert.True(t, errors.Is(SetPeriodicInterrupt(dev, true), os.ErrNotExist))
+	assert.True(t, errors.Is(SetAlarmInterrupt(dev, true), os.ErrNotExist))
+	assert.True(t, errors.Is(SetUpdateInterrupt(dev, true), os.ErrNotExist))
+}
+
+func TestWithRTC(t *testing.T) {
+	var opened *RTC
+	require.NoError(t, WithRTC(os.DevNull, func(c *RTC) error {
+		opened = c
+		return nil
+	}))
+	assert.True(t, errors.Is(opened.Close(), ErrClosed), "device was not closed")
+
+	errFn := errors.New("fn failed")
+	assert.Equal(t, errFn, WithRTC(os.DevNull, func(c *RTC) error {
+		return errFn
+	}))
+
+	assert.True(t, errors.Is(WithRTC(filepath.Join(t.TempDir(), "rtc"), func(c *RTC) error {
+		t.Error("fn called without a device")
+		return nil
+	}), os.ErrNotExist))
+}
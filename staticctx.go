@@ -0,0 +1,184 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"context"
+	"time"
+)
+
+// withRTCCtx opens the real-time clock device and runs fn with it, returning
+// the context's error if the context is done first, so that a wedged device,
+// such as a flaky I2C RTC, cannot hang the caller. The device is closed once
+// fn returns, even if the context is done before. fn is not run if the context
+// is done by the time the device is open, but once it runs it completes, so a
+// change made by fn may take effect after the context's error is returned.
+// Results must only be read from fn's closure when no error is returned.
+func withRTCCtx(ctx context.Context, dev string, opts []Option, fn func(c *RTC) error) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		c, err := NewRTC(dev, opts...)
+		if err != nil {
+			done <- err
+			return
+		}
+		defer c.Close()
+		if err := ctx.Err(); err != nil {
+			done <- err
+			return
+		}
+		done <- fn(c)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GetTimeCtx reads the time from the specified real-time clock device, giving
+// up when the context is done.
+func GetTimeCtx(ctx context.Context, dev string) (t time.Time, err error) {
+	var res time.Time
+	if err := withRTCCtx(ctx, dev, []Option{WithReadOnly()}, func(c *RTC) (err error) {
+		res, err = c.GetTime()
+		return err
+	}); err != nil {
+		return time.Time{}, err
+	}
+	return res, nil
+}
+
+// SetTimeCtx sets the time of the specified real-time clock device, giving up
+// when the context is done. Giving up does not undo the write: if the context
+// is done while the device is being written, the time may still be set after
+// the context's error is returned. The other setters behave the same way.
+func SetTimeCtx(ctx context.Context, dev string, t time.Time) (err error) {
+	return withRTCCtx(ctx, dev, nil, func(c *RTC) error {
+		return c.SetTime(t)
+	})
+}
+
+// GetEpochCtx reads the epoch from the specified real-time clock device,
+// giving up when the context is done.
+func GetEpochCtx(ctx context.Context, dev string) (epoch uint, err error) {
+	var res uint
+	if err := withRTCCtx(ctx, dev, []Option{WithReadOnly()}, func(c *RTC) (err error) {
+		res, err = c.GetEpoch()
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// GetFrequencyCtx returns the periodic interrupt frequency of the specified
+// real-time clock device, giving up when the context is done.
+func GetFrequencyCtx(ctx context.Context, dev string) (frequency uint, err error) {
+	var res uint
+	if err := withRTCCtx(ctx, dev, []Option{WithReadOnly()}, func(c *RTC) (err error) {
+		res, err = c.GetFrequency()
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// SetFrequencyCtx sets the periodic interrupt frequency of the specified
+// real-time clock device, giving up when the context is done.
+func SetFrequencyCtx(ctx context.Context, dev string, frequency uint) (err error) {
+	return withRTCCtx(ctx, dev, nil, func(c *RTC) error {
+		return c.SetFrequency(frequency)
+	})
+}
+
+// SetPeriodicInterruptCtx enables or disables periodic interrupts for the
+// specified real-time clock device, giving up when the context is done.
+func SetPeriodicInterruptCtx(ctx context.Context, dev string, enable bool) (err error) {
+	return withRTCCtx(ctx, dev, nil, func(c *RTC) error {
+		return c.SetPeriodicInterrupt(enable)
+	})
+}
+
+// SetAlarmInterruptCtx enables or disables the alarm interrupt for the
+// specified real-time clock device, giving up when the context is done.
+func SetAlarmInterruptCtx(ctx context.Context, dev string, enable bool) (err error) {
+	return withRTCCtx(ctx, dev, nil, func(c *RTC) error {
+		return c.SetAlarmInterrupt(enable)
+	})
+}
+
+// SetUpdateInterruptCtx enables or disables the update interrupt for the
+// specified real-time clock device, giving up when the context is done.
+func SetUpdateInterruptCtx(ctx context.Context, dev string, enable bool) (err error) {
+	return withRTCCtx(ctx, dev, nil, func(c *RTC) error {
+		return c.SetUpdateInterrupt(enable)
+	})
+}
+
+// GetAlarmCtx returns the alarm time of the specified real-time clock device,
+// giving up when the context is done.
+func GetAlarmCtx(ctx context.Context, dev string) (t time.Time, err error) {
+	var res time.Time
+	if err := withRTCCtx(ctx, dev, []Option{WithReadOnly()}, func(c *RTC) (err error) {
+		res, err = c.GetAlarm()
+		return err
+	}); err != nil {
+		return time.Time{}, err
+	}
+	return res, nil
+}
+
+// SetAlarmCtx sets the alarm time of the specified real-time clock device and
+// returns the effective alarm time, giving up when the context is done.
+func SetAlarmCtx(ctx context.Context, dev string, t time.Time) (effective time.Time, err error) {
+	var res time.Time
+	if err := withRTCCtx(ctx, dev, nil, func(c *RTC) (err error) {
+		res, err = c.SetAlarm(t)
+		return err
+	}); err != nil {
+		return time.Time{}, err
+	}
+	return res, nil
+}
+
+// ReadWakeAlarmCtx returns the state of the wake alarm of the specified
+// real-time clock device, giving up when the context is done.
+func ReadWakeAlarmCtx(ctx context.Context, dev string) (a WakeAlarm, err error) {
+	var res WakeAlarm
+	if err := withRTCCtx(ctx, dev, []Option{WithReadOnly()}, func(c *RTC) (err error) {
+		res, err = c.WakeAlarm()
+		return err
+	}); err != nil {
+		return WakeAlarm{}, err
+	}
+	return res, nil
+}
+
+// SetWakeAlarmCtx sets the wake alarm time of the specified real-time clock
+// device and returns the effective alarm time, giving up when the context is
+// done.
+func SetWakeAlarmCtx(ctx context.Context, dev string, t time.Time) (effective time.Time, err error) {
+	var res time.Time
+	if err := withRTCCtx(ctx, dev, nil, func(c *RTC) (err error) {
+		res, err = c.SetWakeAlarm(t)
+		return err
+	}); err != nil {
+		return time.Time{}, err
+	}
+	return res, nil
+}
+
+// CancelWakeAlarmCtx cancels the wake alarm of the specified real-time clock
+// device, giving up when the context is done.
+func CancelWakeAlarmCtx(ctx context.Context, dev string) (err error) {
+	return withRTCCtx(ctx, dev, nil, func(c *RTC) error {
+		return c.CancelWakeAlarm()
+	})
+}
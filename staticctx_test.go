@@ -0,0 +1,39 @@
+package rtc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRTCCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	err := withRTCCtx(ctx, os.DevNull, nil, func(c *RTC) error {
+		called = true
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, called)
+
+	release := make(chan struct{})
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = withRTCCtx(ctx, os.DevNull, nil, func(c *RTC) error {
+		<-release
+		return nil
+	})
+	close(release)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	require.NoError(t, withRTCCtx(context.Background(), os.DevNull, nil, func(c *RTC) error {
+		assert.GreaterOrEqual(t, c.fd(), 0)
+		return nil
+	}))
+}
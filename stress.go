@@ -0,0 +1,110 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// StressReport summarizes a StressTest run.
+type StressReport struct {
+	// Frequency is the periodic interrupt frequency that was used, in Hz.
+	Frequency uint
+	// Duration is the measured length of the run.
+	Duration time.Duration
+	// Expected is the number of interrupts expected at Frequency over Duration.
+	Expected uint64
+	// Interrupts is the number of interrupts reported by the kernel.
+	Interrupts uint64
+	// Reads is the number of reads that returned interrupts.
+	Reads uint64
+	// Coalesced is the number of interrupts the kernel merged into a single
+	// read because userspace did not read them in time.
+	Coalesced uint64
+	// Dropped is the number of expected interrupts that were never reported.
+	Dropped uint64
+	// MaxGap is the longest time between two consecutive reads.
+	MaxGap time.Duration
+}
+
+// StressTest drives periodic interrupts on the specified real-time clock
+// device at the highest frequency it accepts for the given duration, and
+// reports how many interrupts were coalesced by the kernel or dropped.
+// The device's frequency is restored afterwards.
+// It is intended for qualifying kernels and boards before deployment.
+func StressTest(dev string, duration time.Duration) (report StressReport, err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return report, err
+	}
+	defer c.Close()
+
+	origFreq, err := c.GetFrequency()
+	if err != nil {
+		return report, err
+	}
+
+	// Unprivileged users are limited by /proc/sys/dev/rtc/max-user-freq,
+	// so step down from the hardware maximum until a frequency is accepted.
+	var freq uint
+	for freq = unix.RTC_MAX_FREQ; freq >= 2; freq /= 2 {
+		err = c.SetFrequency(freq)
+		if err == nil || !errors.Is(err, syscall.EACCES) {
+			break
+		}
+	}
+	if err != nil {
+		return report, err
+	}
+	defer func() {
+		_ = c.SetFrequency(origFreq)
+	}()
+
+	if err := c.SetPeriodicInterrupt(true); err != nil {
+		return report, err
+	}
+	defer func() {
+		_ = c.SetPeriodicInterrupt(false)
+	}()
+
+	report.Frequency = freq
+	buf := make([]byte, 4)
+	start := time.Now()
+	prev := start
+	for {
+		if _, err := syscall.Read(c.fd(), buf); err != nil {
+			return report, fmt.Errorf("failed to read real-time clock interrupt: %w", wrapErrno(err))
+		}
+		now := time.Now()
+
+		// buf[1:3] = number of interrupts since last read
+		cnt := uint64(binary.LittleEndian.Uint32(buf) >> 8)
+		report.Reads++
+		report.Interrupts += cnt
+		if cnt > 1 {
+			report.Coalesced += cnt - 1
+		}
+		if gap := now.Sub(prev); gap > report.MaxGap {
+			report.MaxGap = gap
+		}
+		prev = now
+
+		if now.Sub(start) >= duration {
+			break
+		}
+	}
+
+	report.Duration = prev.Sub(start)
+	report.Expected = uint64(report.Duration.Seconds() * float64(freq))
+	if report.Expected > report.Interrupts {
+		report.Dropped = report.Expected - report.Interrupts
+	}
+	return report, nil
+}
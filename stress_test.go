@@ -0,0 +1,19 @@
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStressTest(t *testing.T) {
+	report, err := StressTest("/dev/rtc", time.Second)
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, report.Frequency, uint(2))
+	assert.GreaterOrEqual(t, report.Duration.Nanoseconds(), time.Second.Nanoseconds())
+	assert.NotZero(t, report.Reads)
+	assert.GreaterOrEqual(t, report.Interrupts, report.Reads)
+}
@@ -0,0 +1,168 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// parseDevNumber parses a device number in the "major:minor" format of sysfs dev files.
+func parseDevNumber(s string) (dev uint64, err error) {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("malformed device number %q", s)
+	}
+	major, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("malformed device number %q: %w", s, err)
+	}
+	minor, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("malformed device number %q: %w", s, err)
+	}
+	return unix.Mkdev(uint32(major), uint32(minor)), nil
+}
+
+// NewRTCFromSysfs opens the real-time clock class device with the given name,
+// such as "rtc0", without relying on its /dev node.
+// The device number is read from /sys/class/rtc/<name>/dev and a temporary
+// device node is created under /dev to open the device, then removed. This
+// allows Go based init systems to use the RTC in an initramfs before udev has
+// populated /dev. The node is not created in the temporary directory, which is
+// often missing there or mounted nodev. Creating the node requires the
+// CAP_MKNOD capability.
+func NewRTCFromSysfs(name string, opts ...Option) (*RTC, error) {
+	b, err := os.ReadFile(filepath.Join(rtcClassDir(), name, "dev"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rtc: %w", err)
+	}
+	dev, err := parseDevNumber(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rtc: %w", err)
+	}
+
+	dir, err := os.MkdirTemp(devDir(), ".rtc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rtc: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := filepath.Join(dir, name)
+	if err := unix.Mknod(node, unix.S_IFCHR|0600, int(dev)); err != nil {
+		return nil, fmt.Errorf("failed to create rtc device node: %w", wrapErrno(err))
+	}
+	return NewRTC(node, opts...)
+}
+
+// rdev returns the device number of the open device.
+func (c *RTC) rdev() (dev uint64, err error) {
+	var st unix.Stat_t
+	if err := unix.Fstat(c.fd(), &st); err != nil {
+		return 0, fmt.Errorf("failed to stat real-time clock: %w", wrapErrno(err))
+	}
+	return uint64(st.Rdev), nil
+}
+
+// sysfsDir returns the sysfs directory of the open device, such as
+// /sys/class/rtc/rtc0, found by matching its device number.
+func (c *RTC) sysfsDir() (dir string, err error) {
+	rdev, err := c.rdev()
+	if err != nil {
+		return "", err
+	}
+	dirs, err := filepath.Glob(filepath.Join(rtcClassDir(), "rtc*"))
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		b, err := os.ReadFile(filepath.Join(dir, "dev"))
+		if err != nil {
+			continue
+		}
+		if dev, err := parseDevNumber(string(b)); err == nil && dev == rdev {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find real-time clock in %s: %w", rtcClassDir(), os.ErrNotExist)
+}
+
+// HctosysDevice returns the device node, such as /dev/rtc0, of the real-time
+// clock the kernel used to initialize the system clock at boot. Systems with
+// several RTCs can use it to pick the authoritative one. If no RTC was used,
+// the returned error wraps os.ErrNotExist.
+func HctosysDevice() (dev string, err error) {
+	name, err := hctosysName(rtcClassDir())
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(devDir(), name), nil
+}
+
+// hctosysName returns the name of the class device in classDir whose hctosys
+// attribute is set.
+func hctosysName(classDir string) (name string, err error) {
+	dirs, err := filepath.Glob(filepath.Join(classDir, "rtc*"))
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		if readHctosys(dir) {
+			return filepath.Base(dir), nil
+		}
+	}
+	return "", fmt.Errorf("no real-time clock initialized the system clock: %w", os.ErrNotExist)
+}
+
+// readHctosys reports whether the hctosys attribute in the sysfs directory dir
+// is set.
+func readHctosys(dir string) bool {
+	b, err := os.ReadFile(filepath.Join(dir, "hctosys"))
+	return err == nil && strings.TrimSpace(string(b)) == "1"
+}
+
+// Hctosys reports whether the kernel used the real-time clock to initialize
+// the system clock at boot.
+func (c *RTC) Hctosys() (hctosys bool, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return false, err
+	}
+	return readHctosys(dir), nil
+}
+
+// GetTimeSysfs returns the time of the real-time clock class device with the
+// given name, such as "rtc0", read from its since_epoch sysfs attribute, or
+// from its date and time attributes on kernels without it. Unlike GetTime it
+// does not open the device, so unprivileged monitoring processes can read the
+// RTC time. The time is read as UTC.
+func GetTimeSysfs(name string) (t time.Time, err error) {
+	return readSysfsClockTime(filepath.Join(rtcClassDir(), name))
+}
+
+// readSysfsClockTime reads the RTC time from the sysfs directory dir.
+func readSysfsClockTime(dir string) (t time.Time, err error) {
+	if t, ok := readSysfsTime(filepath.Join(dir, "since_epoch")); ok {
+		return t, nil
+	}
+	date, err := os.ReadFile(filepath.Join(dir, "date"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read real-time clock date: %w", err)
+	}
+	tod, err := os.ReadFile(filepath.Join(dir, "time"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read real-time clock time: %w", err)
+	}
+	t, err = time.Parse("2006-01-02 15:04:05", strings.TrimSpace(string(date))+" "+strings.TrimSpace(string(tod)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse real-time clock time: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,253 @@
+package rtc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestParseDevNumber(t *testing.T) {
+	dev, err := parseDevNumber("252:1\n")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(252), unix.Major(dev))
+	assert.Equal(t, uint32(1), unix.Minor(dev))
+
+	_, err = parseDevNumber("252")
+	assert.Error(t, err)
+	_, err = parseDevNumber("a:b")
+	assert.Error(t, err)
+}
+
+func TestNewRTCFromSysfs(t *testing.T) {
+	c, err := NewRTCFromSysfs("rtc0")
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.GetTime()
+	assert.NoError(t, err)
+}
+
+func TestHctosysName(t *testing.T) {
+	dir := t.TempDir()
+	for name, hctosys := range map[string]string{"rtc0": "0\n", "rtc1": "1\n"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "hctosys"), []byte(hctosys), 0644))
+	}
+
+	name, err := hctosysName(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "rtc1", name)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rtc1", "hctosys"), []byte("0\n"), 0644))
+	_, err = hctosysName(dir)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestReadDeviceInfo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "rtc1")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "device"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "name"), []byte("rtc-pcf8563 1-0051\n"), 0644))
+	require.NoError(t, os.Symlink("../../../bus/i2c/drivers/rtc-pcf8563", filepath.Join(dir, "device", "driver")))
+
+	info := readDeviceInfo(dir)
+	assert.Equal(t, DeviceInfo{
+		Name:      "rtc1",
+		ChipName:  "rtc-pcf8563 1-0051",
+		Driver:    "rtc-pcf8563",
+		SysfsPath: dir,
+		DevNode:   "/dev/rtc1",
+	}, info)
+}
+
+func TestWriteSysfsWakeAlarm(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wakealarm")
+
+	require.NoError(t, writeSysfsWakeAlarm(dir, 1900000000))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "1900000000", string(b))
+
+	require.NoError(t, writeSysfsWakeAlarm(dir, 0))
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "0", string(b))
+}
+
+// TestWakeAlarmFallback checks that only drivers lacking RTC_WKALM_SET fall
+// back to the wakealarm attribute, so that a rejected request does not cancel
+// the current wake alarm.
+func TestWakeAlarmFallback(t *testing.T) {
+	sys := t.TempDir()
+	SetRoots("", sys, "")
+	defer SetRoots("", "", "")
+	dir := filepath.Join(sys, "class", "rtc", "rtc0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	// Pipes have device number 0:0.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dev"), []byte("0:0\n"), 0644))
+	path := filepath.Join(dir, "wakealarm")
+	require.NoError(t, os.WriteFile(path, []byte("1900000000"), 0644))
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+	defer c.Close()
+
+	for _, at := range []time.Time{time.Now().Add(time.Hour), {}} {
+		_, ok, err := c.wakeAlarmFallback(unix.EINVAL, at)
+		assert.False(t, ok)
+		assert.NoError(t, err)
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "1900000000", string(b), "wake alarm changed after EINVAL")
+	}
+
+	_, ok, err := c.wakeAlarmFallback(unix.ENOTTY, time.Time{})
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "0", string(b))
+}
+
+func TestReadSysfsClockTime(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "date"), []byte("2030-03-04\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "time"), []byte("05:06:07\n"), 0644))
+	tm, err := readSysfsClockTime(dir)
+	require.NoError(t, err)
+	assert.True(t, time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC).Equal(tm))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "since_epoch"), []byte("1900000000\n"), 0644))
+	tm, err = readSysfsClockTime(dir)
+	require.NoError(t, err)
+	assert.True(t, time.Unix(1900000000, 0).Equal(tm))
+
+	_, err = readSysfsClockTime(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestReadHwmonTemperature(t *testing.T) {
+	dir := t.TempDir()
+	_, err := readHwmonTemperature(dir)
+	assert.True(t, errors.Is(err, ErrNotSupported))
+
+	hwmon := filepath.Join(dir, "hwmon", "hwmon2")
+	require.NoError(t, os.MkdirAll(hwmon, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(hwmon, "temp1_input"), []byte("24750\n"), 0644))
+	celsius, err := readHwmonTemperature(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 24.75, celsius)
+}
+
+func TestReadClockInfo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "rtc0")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "device", "power"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "name"), []byte("rtc_cmos\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hctosys"), []byte("1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "device", "power", "wakeup"), []byte("disabled\n"), 0644))
+
+	info := readClockInfo(dir)
+	assert.Equal(t, "rtc_cmos", info.ChipName)
+	assert.True(t, info.Hctosys)
+	assert.True(t, info.WakeupCapable)
+	assert.Nil(t, info.Features)
+	_, err := os.Stat(info.DevNode)
+	assert.Equal(t, err == nil, info.HasDevNode)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "device", "power", "wakeup")))
+	assert.False(t, readClockInfo(dir).WakeupCapable)
+}
+
+func TestFindByName(t *testing.T) {
+	classDir := t.TempDir()
+	rtc0 := filepath.Join(classDir, "rtc0")
+	rtc1 := filepath.Join(classDir, "rtc1")
+	require.NoError(t, os.MkdirAll(filepath.Join(rtc0, "device"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(rtc1, "device"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rtc0, "name"), []byte("snvs_rtc\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rtc1, "name"), []byte("rtc-ds1307 1-0068\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rtc1, "device", "name"), []byte("ds3231\n"), 0644))
+
+	for name, want := range map[string]string{
+		"snvs_rtc":   rtc0,
+		"DS3231":     rtc1,
+		"rtc-ds1307": rtc1,
+		"ds1307":     rtc1,
+	} {
+		dir, err := findByName(classDir, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, dir, name)
+	}
+
+	_, err := findByName(classDir, "pcf8563")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestDefaultClock(t *testing.T) {
+	classDir, devDir := t.TempDir(), t.TempDir()
+	_, err := defaultClock(classDir, devDir)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	require.NoError(t, os.WriteFile(filepath.Join(devDir, "rtc0"), nil, 0600))
+	dev, err := defaultClock(classDir, devDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(devDir, "rtc0"), dev)
+
+	require.NoError(t, os.Symlink("rtc0", filepath.Join(devDir, "rtc")))
+	dev, err = defaultClock(classDir, devDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(devDir, "rtc"), dev)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(classDir, "rtc1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(classDir, "rtc1", "hctosys"), []byte("1\n"), 0644))
+	dev, err = defaultClock(classDir, devDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(devDir, "rtc1"), dev)
+}
+
+func TestSetRoots(t *testing.T) {
+	dev, sys := t.TempDir(), t.TempDir()
+	SetRoots(dev, sys, "")
+	defer SetRoots("", "", "")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(sys, "class", "rtc", "rtc1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sys, "class", "rtc", "rtc1", "hctosys"), []byte("1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dev, "rtc1"), nil, 0600))
+
+	clocks, err := GetClocks()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dev, "rtc1")}, clocks)
+
+	d, err := DefaultClock()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dev, "rtc1"), d)
+	assert.Equal(t, "/proc/driver/rtc", procDriverRtcPath())
+}
+
+func TestWakeup(t *testing.T) {
+	dir := t.TempDir()
+	capable, enabled := readWakeup(dir)
+	assert.False(t, capable)
+	assert.False(t, enabled)
+	assert.True(t, errors.Is(writeWakeup(dir, true), ErrNotSupported))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "device", "power"), 0755))
+	require.NoError(t, os.WriteFile(wakeupPath(dir), []byte("disabled\n"), 0644))
+	capable, enabled = readWakeup(dir)
+	assert.True(t, capable)
+	assert.False(t, enabled)
+
+	require.NoError(t, writeWakeup(dir, true))
+	capable, enabled = readWakeup(dir)
+	assert.True(t, capable)
+	assert.True(t, enabled)
+}
@@ -0,0 +1,139 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SystemdWake describes a wake schedule as a systemd timer unit with
+// WakeSystem=true, so that hosts managed by systemd can have the RTC wake
+// alarm armed by systemd itself.
+type SystemdWake struct {
+	// Name is the unit name without the .timer or .service suffix.
+	Name string
+	// Description is the unit description.
+	Description string
+	// OnCalendar is a systemd calendar expression, such as "*-*-* 03:00:00".
+	OnCalendar string
+	// Command is run by the oneshot service unit activated by the timer. If
+	// empty, the service runs /bin/true, since systemd refuses to start a
+	// timer without a unit to activate, so the timer only wakes the system.
+	Command string
+}
+
+// SystemdWakeAt returns a SystemdWake that wakes the system once at time t.
+func SystemdWakeAt(name string, t time.Time, command string) SystemdWake {
+	return SystemdWake{
+		Name:        name,
+		Description: fmt.Sprintf("Wake system at %s", t.UTC().Format(time.RFC3339)),
+		OnCalendar:  t.UTC().Format("2006-01-02 15:04:05") + " UTC",
+		Command:     command,
+	}
+}
+
+// TimerUnit returns the contents of the .timer unit.
+func (w SystemdWake) TimerUnit() string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	if w.Description != "" {
+		fmt.Fprintf(&b, "Description=%s\n", w.Description)
+	}
+	b.WriteString("\n[Timer]\n")
+	fmt.Fprintf(&b, "OnCalendar=%s\n", w.OnCalendar)
+	b.WriteString("WakeSystem=true\n")
+	fmt.Fprintf(&b, "Unit=%s.service\n", w.Name)
+	b.WriteString("\n[Install]\nWantedBy=timers.target\n")
+	return b.String()
+}
+
+// ServiceUnit returns the contents of the .service unit.
+func (w SystemdWake) ServiceUnit() string {
+	command := w.Command
+	if command == "" {
+		command = "/bin/true"
+	}
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	if w.Description != "" {
+		fmt.Fprintf(&b, "Description=%s\n", w.Description)
+	}
+	b.WriteString("\n[Service]\nType=oneshot\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", command)
+	return b.String()
+}
+
+// WriteSystemdUnits writes the timer and service units of each wake to dir,
+// typically /etc/systemd/system. The units still need to be enabled, for
+// example with systemctl enable --now <name>.timer.
+func WriteSystemdUnits(dir string, wakes []SystemdWake) (err error) {
+	for _, w := range wakes {
+		if w.Name == "" || w.OnCalendar == "" {
+			return errors.New("systemd wake requires a name and a calendar expression")
+		}
+		if err := os.WriteFile(filepath.Join(dir, w.Name+".timer"), []byte(w.TimerUnit()), 0644); err != nil {
+			return fmt.Errorf("failed to write systemd timer unit: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, w.Name+".service"), []byte(w.ServiceUnit()), 0644); err != nil {
+			return fmt.Errorf("failed to write systemd service unit: %w", err)
+		}
+	}
+	return nil
+}
+
+// ParseSystemdTimer imports a wake schedule from the .timer unit named name,
+// such as "backup.timer", read from r. Only timers with WakeSystem=true and an
+// OnCalendar expression are accepted. The Command is not filled in since it
+// belongs to the activated service unit.
+func ParseSystemdTimer(name string, r io.Reader) (w SystemdWake, err error) {
+	w.Name = strings.TrimSuffix(name, ".timer")
+	wake := false
+	section := ""
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch {
+		case section == "Unit" && key == "Description":
+			w.Description = value
+		case section == "Timer" && key == "OnCalendar":
+			w.OnCalendar = value
+		case section == "Timer" && key == "WakeSystem":
+			switch strings.ToLower(value) {
+			case "1", "yes", "true", "on":
+				wake = true
+			default:
+				wake = false
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return w, fmt.Errorf("failed to read systemd timer unit: %w", err)
+	}
+	if !wake {
+		return w, fmt.Errorf("systemd timer %s does not wake the system", name)
+	}
+	if w.OnCalendar == "" {
+		return w, fmt.Errorf("systemd timer %s has no OnCalendar expression", name)
+	}
+	return w, nil
+}
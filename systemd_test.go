@@ -0,0 +1,57 @@
+package rtc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdWake(t *testing.T) {
+	w := SystemdWakeAt("backup", time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC), "/usr/bin/backup")
+	assert.Equal(t, "2030-01-02 03:04:05 UTC", w.OnCalendar)
+
+	dir := t.TempDir()
+	require.NoError(t, WriteSystemdUnits(dir, []SystemdWake{w}))
+
+	timer, err := os.ReadFile(filepath.Join(dir, "backup.timer"))
+	require.NoError(t, err)
+	assert.Contains(t, string(timer), "WakeSystem=true\n")
+	assert.Contains(t, string(timer), "Unit=backup.service\n")
+
+	service, err := os.ReadFile(filepath.Join(dir, "backup.service"))
+	require.NoError(t, err)
+	assert.Contains(t, string(service), "ExecStart=/usr/bin/backup\n")
+
+	parsed, err := ParseSystemdTimer("backup.timer", strings.NewReader(string(timer)))
+	require.NoError(t, err)
+	assert.Equal(t, w.Name, parsed.Name)
+	assert.Equal(t, w.Description, parsed.Description)
+	assert.Equal(t, w.OnCalendar, parsed.OnCalendar)
+}
+
+func TestSystemdWakeWithoutCommand(t *testing.T) {
+	w := SystemdWakeAt("wake", time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC), "")
+
+	dir := t.TempDir()
+	require.NoError(t, WriteSystemdUnits(dir, []SystemdWake{w}))
+
+	// systemd refuses to start a timer whose unit is missing
+	timer, err := os.ReadFile(filepath.Join(dir, "wake.timer"))
+	require.NoError(t, err)
+	assert.Contains(t, string(timer), "Unit=wake.service\n")
+
+	service, err := os.ReadFile(filepath.Join(dir, "wake.service"))
+	require.NoError(t, err)
+	assert.Contains(t, string(service), "Type=oneshot\n")
+	assert.Contains(t, string(service), "ExecStart=/bin/true\n")
+}
+
+func TestParseSystemdTimerWithoutWake(t *testing.T) {
+	_, err := ParseSystemdTimer("logrotate.timer", strings.NewReader("[Timer]\nOnCalendar=daily\n"))
+	assert.Error(t, err)
+}
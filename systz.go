@@ -0,0 +1,47 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// timezone mirrors the kernel's struct timezone used by settimeofday(2).
+type timezone struct {
+	MinutesWest int32
+	DstTime     int32
+}
+
+// Systz sets the kernel timezone from the local time zone, equivalent to
+// hwclock --systz.
+// The kernel warps the system clock on the first timezone update after boot,
+// which compensates for having read a local time RTC as UTC. If localtime is
+// true, the RTC is assumed to keep local time and the warp is allowed to
+// happen. Otherwise the warp is locked first so that the system clock is left
+// untouched.
+func Systz(localtime bool) (err error) {
+	_, offset := time.Now().Zone()
+	tz := &timezone{
+		MinutesWest: int32(-offset / 60),
+	}
+
+	if !localtime {
+		// A zero offset on the first call consumes the warp without moving the clock.
+		if err := settimeofdayTz(&timezone{}); err != nil {
+			return err
+		}
+	}
+	return settimeofdayTz(tz)
+}
+
+// settimeofdayTz sets the kernel timezone without changing the system time.
+func settimeofdayTz(tz *timezone) (err error) {
+	if _, _, errno := syscall.Syscall(syscall.SYS_SETTIMEOFDAY, 0, uintptr(unsafe.Pointer(tz)), 0); errno != 0 {
+		return fmt.Errorf("failed to set kernel timezone: %w", wrapErrno(errno))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Temperature returns the die temperature in degrees Celsius of real-time
+// clocks with a built-in thermometer, such as the DS3231, read from the hwmon
+// device registered by the driver. It is useful for correlating drift with
+// temperature. For other devices the returned error wraps ErrNotSupported.
+func (c *RTC) Temperature() (celsius float64, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return 0, err
+	}
+	return readHwmonTemperature(filepath.Join(dir, "device"))
+}
+
+// readHwmonTemperature reads the first temperature input of the hwmon devices
+// registered under the sysfs device directory dir.
+func readHwmonTemperature(dir string) (celsius float64, err error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "hwmon", "hwmon*", "temp1_input"))
+	if err != nil {
+		return 0, err
+	}
+	if len(paths) == 0 {
+		return 0, fmt.Errorf("failed to find real-time clock temperature sensor: %w", ErrNotSupported)
+	}
+	b, err := os.ReadFile(paths[0])
+	if err != nil {
+		return 0, fmt.Errorf("failed to read real-time clock temperature: %w", wrapErrno(err))
+	}
+	// hwmon reports temperatures in millidegrees Celsius.
+	milli, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse real-time clock temperature: %w", err)
+	}
+	return float64(milli) / 1000, nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"syscall"
 	"time"
@@ -25,14 +26,80 @@ type Ticker struct {
 	rtc   *RTC
 	t     time.Time
 	wait  sync.WaitGroup
+	w     *waiter
+	every uint
+	rate  uint
 	C     <-chan Tick
+	// Err receives the error that stopped the Ticker, such as a failure to
+	// read the device. No more ticks are delivered after it.
+	Err <-chan error
+
+	mu     sync.Mutex
+	paused bool
 }
 
 func NewTicker(dev string, frequency uint) (*Ticker, error) {
 	if frequency == 0 {
 		return nil, errors.New("zero frequency for NewTicker")
 	}
+	return newTicker(dev, frequency, 1)
+}
+
+// Periodic interrupt rates supported by the Linux RTC interface, in Hz.
+// Rates above the default max_user_freq require CAP_SYS_RESOURCE.
+const (
+	minTickerRate  = 2
+	userTickerRate = 64
+	maxTickerRate  = 8192
+)
+
+// tickerIntervalTolerance is the relative error in the interval that
+// NewTickerInterval accepts to keep the interrupt rate low.
+const tickerIntervalTolerance = 0.01
+
+// NewTickerInterval creates a Ticker that ticks every d, approximately. It
+// picks the lowest power-of-two interrupt rate from which d can be obtained
+// within 1% by delivering every nth interrupt, or failing that the rate up to
+// 64 Hz that comes closest. Intervals shorter than 1/64 s use the nearest
+// faster rate, which requires CAP_SYS_RESOURCE unless the max_user_freq of the
+// device allows it. Use Interval for the resulting interval.
+//
+// The Frame of a tick counts the ticks delivered, wrapping to zero every
+// frequency/every ticks, where frequency is the interrupt rate and every the
+// number of interrupts per tick. This is only every second if d divides a
+// second: with d of 300ms, for example, it wraps every 3 ticks, or 900ms.
+// Missed counts whole intervals missed.
+func NewTickerInterval(dev string, d time.Duration) (*Ticker, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("invalid interval %v for NewTickerInterval", d)
+	}
+	frequency, every := tickerRate(d)
+	return newTicker(dev, frequency, every)
+}
+
+// tickerRate returns the interrupt rate and decimation factor that best
+// approximate the interval d.
+func tickerRate(d time.Duration) (frequency, every uint) {
+	bestErr := -1.0
+	for f := uint(minTickerRate); f <= maxTickerRate; f *= 2 {
+		n := uint(math.Round(d.Seconds() * float64(f)))
+		if n == 0 {
+			n = 1
+		}
+		interval := time.Duration(n) * time.Second / time.Duration(f)
+		relErr := math.Abs(float64(interval-d)) / float64(d)
+		if bestErr < 0 || relErr < bestErr {
+			frequency, every, bestErr = f, n, relErr
+		}
+		if relErr <= tickerIntervalTolerance || (f >= userTickerRate && d.Seconds()*float64(f) >= 1) {
+			break
+		}
+	}
+	return frequency, every
+}
 
+// newTicker starts a Ticker delivering every nth interrupt at the given rate.
+func newTicker(dev string, frequency, every uint) (*Ticker, error) {
 	c, err := NewRTC(dev)
 	if err != nil {
 		return nil, err
@@ -43,26 +110,48 @@ func NewTicker(dev string, frequency uint) (*Ticker, error) {
 		return nil, err
 	}
 
+	w, err := newWaiter()
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+
 	if err := c.SetPeriodicInterrupt(true); err != nil {
+		w.close()
 		_ = c.Close()
 		return nil, err
 	}
 
 	// Give the channel a 1-element time buffer.
-	// If the client falls behind while reading, we drop ticks
-	// until the client catches up.
+	// If the client falls behind while reading, the interrupts that occur
+	// until it catches up are reported in Missed.
 	ch := make(chan Tick, 1)
+	errc := make(chan error, 1)
 	buf := make([]byte, 4)
 	t := &Ticker{
 		done:  make(chan struct{}),
 		rtc:   c,
+		w:     w,
+		every: every,
+		rate:  frequency,
 		frame: 0,
 		t:     time.Now(),
 		C:     ch,
+		Err:   errc,
+	}
+
+	// Frames wrap every frequency/every ticks, which is a second only if
+	// every divides frequency, or every tick if ticks are a second apart or
+	// more.
+	frames := frequency / every
+	if frames == 0 {
+		frames = 1
 	}
 
 	t.wait.Add(1)
 	go func() {
+		// Interrupts counted towards the next tick.
+		var pending uint
 		defer t.wait.Done()
 	loop:
 		for {
@@ -72,9 +161,20 @@ func NewTicker(dev string, frequency uint) (*Ticker, error) {
 			default:
 			}
 
-			_, err := syscall.Read(c.fd, buf)
+			// Wait rather than block in read, so that Stop can interrupt
+			// a paused ticker.
+			readable, err := w.wait(c.fd(), -1)
+			if err != nil {
+				errc <- err
+				break
+			}
+			if !readable {
+				continue
+			}
+
+			_, err = syscall.Read(c.fd(), buf)
 			if err != nil {
-				fmt.Printf("got error reading interrupt, breaking loop: %v\n", err)
+				errc <- fmt.Errorf("failed to read real-time clock interrupt: %w", wrapErrno(err))
 				break
 			}
 
@@ -85,12 +185,24 @@ func NewTicker(dev string, frequency uint) (*Ticker, error) {
 			//fmt.Printf("r: 0x%X, types: 0x%X\n", r, irqTypes)
 			cnt := r >> 8
 
+			// Deliver only every nth interrupt.
+			pending += uint(cnt)
+			if pending < every {
+				continue
+			}
+			ticks := pending / every
+			pending %= every
+
 			now := time.Now()
-			ch <- Tick{
+			select {
+			case ch <- Tick{
 				Time:   now,
 				Delta:  now.Sub(t.t),
 				Frame:  t.frame,
-				Missed: cnt - 1,
+				Missed: uint32(ticks - 1),
+			}:
+			case <-t.done:
+				break loop
 			}
 
 			// Save current time
@@ -98,20 +210,69 @@ func NewTicker(dev string, frequency uint) (*Ticker, error) {
 
 			// Increment frame count
 			t.frame = t.frame + 1
-			if t.frame >= frequency {
+			if t.frame >= frames {
 				t.frame = 0
 			}
 		}
 
 		// Disable interrupts and close RTC device
+		t.mu.Lock()
 		_ = c.SetPeriodicInterrupt(false)
 		_ = c.Close()
+		t.mu.Unlock()
 	}()
 
 	return t, nil
 }
 
+// Interval returns the interval between ticks.
+func (t *Ticker) Interval() time.Duration {
+	return time.Duration(t.every) * time.Second / time.Duration(t.rate)
+}
+
 func (t *Ticker) Stop() {
 	close(t.done)
+	t.w.wake()
 	t.wait.Wait()
+	// The waiter is closed only once the loop is done with it, so that
+	// waking it above never writes to a reused descriptor.
+	t.w.close()
+}
+
+// Pause disables the periodic interrupt, stopping the ticks until Resume is
+// called. The device stays open and C, the frame counter and the missed tick
+// accounting are kept, so the Delta of the first tick after Resume includes
+// the pause. A tick already pending may still be delivered.
+func (t *Ticker) Pause() (err error) {
+	return t.setPaused(true)
+}
+
+// Resume re-enables the periodic interrupt of a paused Ticker.
+func (t *Ticker) Resume() (err error) {
+	return t.setPaused(false)
+}
+
+// Paused reports whether the Ticker is paused.
+func (t *Ticker) Paused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.paused
+}
+
+func (t *Ticker) setPaused(paused bool) (err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.done:
+		return fmt.Errorf("ticker stopped: %w", ErrClosed)
+	default:
+	}
+	if t.paused == paused {
+		return nil
+	}
+	if err := t.rtc.SetPeriodicInterrupt(!paused); err != nil {
+		return err
+	}
+	t.paused = paused
+	return nil
 }
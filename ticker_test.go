@@ -1,6 +1,7 @@
 package rtc
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -54,3 +55,94 @@ loop:
 	// Expect the tick count to equal the ticker's frequency.
 	assert.Equal(t, frequencyHz, tickCount)
 }
+
+// TestTickerPause checks that a paused ticker stops ticking and resumes with its frame count.
+func TestTickerPause(t *testing.T) {
+	ticker, err := NewTicker("/dev/rtc", 4)
+	require.NoError(t, err)
+	defer ticker.Stop()
+
+	tick := <-ticker.C
+	require.NoError(t, ticker.Pause())
+	assert.True(t, ticker.Paused())
+
+	// Drain a tick that was already pending.
+	frame := tick.Frame
+	select {
+	case tick = <-ticker.C:
+		frame = tick.Frame
+	case <-time.After(300 * time.Millisecond):
+	}
+	select {
+	case <-ticker.C:
+		t.Fatal("paused ticker ticked")
+	case <-time.After(time.Second):
+	}
+
+	require.NoError(t, ticker.Resume())
+	assert.False(t, ticker.Paused())
+	tick = <-ticker.C
+	assert.Equal(t, (frame+1)%4, tick.Frame)
+}
+
+// TestTickerStopPaused checks that a paused ticker can be stopped.
+func TestTickerStopPaused(t *testing.T) {
+	ticker, err := NewTicker("/dev/rtc", 2)
+	require.NoError(t, err)
+	require.NoError(t, ticker.Pause())
+	ticker.Stop()
+	assert.True(t, errors.Is(ticker.Resume(), ErrClosed))
+}
+
+func TestTickerRate(t *testing.T) {
+	tests := []struct {
+		d         time.Duration
+		frequency uint
+		every     uint
+	}{
+		{250 * time.Millisecond, 4, 1},
+		{500 * time.Millisecond, 2, 1},
+		{3 * time.Second, 2, 6},
+		{100 * time.Millisecond, 32, 3},
+		{300 * time.Millisecond, 64, 19},
+		{10 * time.Millisecond, 128, 1},
+		{time.Millisecond, 1024, 1},
+		{time.Microsecond, 8192, 1},
+	}
+	for _, test := range tests {
+		frequency, every := tickerRate(test.d)
+		assert.Equal(t, test.frequency, frequency, "frequency for %v", test.d)
+		assert.Equal(t, test.every, every, "decimation for %v", test.d)
+	}
+}
+
+// TestTickerInterval checks that a decimated ticker ticks at the requested interval.
+func TestTickerInterval(t *testing.T) {
+	ticker, err := NewTickerInterval("/dev/rtc", 750*time.Millisecond)
+	require.NoError(t, err)
+	defer ticker.Stop()
+	assert.Equal(t, 750*time.Millisecond, ticker.Interval())
+
+	prev := <-ticker.C
+	tick := <-ticker.C
+	assert.Equal(t, uint32(0), tick.Missed)
+	assert.WithinDuration(t, prev.Time.Add(750*time.Millisecond), tick.Time, 5*time.Millisecond)
+}
+
+// TestTickerStopUnread checks that a ticker whose ticks are not read can be stopped.
+func TestTickerStopUnread(t *testing.T) {
+	ticker, err := NewTicker("/dev/rtc", 16)
+	require.NoError(t, err)
+	time.Sleep(300 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		ticker.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked on an unread tick")
+	}
+}
@@ -4,134 +4,715 @@
 package rtc
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
+
+	"golang.org/x/sys/unix"
 )
 
+// Alarm is delivered by a Timer when it expires.
 type Alarm struct {
+	// Time is the system time at which the Alarm was delivered.
+	Time time.Time
+	// RTCTime is the time read from the device at expiry, or the zero time if
+	// it could not be read.
+	RTCTime time.Time
+	// Event holds the interrupt flags of the last interrupt read before the
+	// Alarm was delivered. It is zero if the Timer fired without waiting.
+	Event Event
+	// Suspended is set when the system appears to have been suspended and
+	// resumed while the Timer was waiting.
+	Suspended bool
+	// Missed is set when the alarm time passed while the system was suspended
+	// without the interrupt being delivered, and the Alarm was delivered on
+	// resume instead. See MissedAlarmPolicy.
+	Missed bool
+}
+
+// suspendThreshold is how far CLOCK_BOOTTIME may run ahead of CLOCK_MONOTONIC
+// before a suspend is assumed.
+const suspendThreshold = time.Second
+
+// suspendClock is a paired reading of CLOCK_BOOTTIME, which keeps counting
+// while the system is suspended, and CLOCK_MONOTONIC, which does not.
+type suspendClock struct {
+	boot, mono time.Duration
+}
+
+// readSuspendClock reads both clocks. Failures leave the readings at zero,
+// which reports no suspend.
+func readSuspendClock() (s suspendClock) {
+	s.boot, _ = clockNow(unix.CLOCK_BOOTTIME)
+	s.mono, _ = clockNow(unix.CLOCK_MONOTONIC)
+	return s
+}
+
+// suspendedSince reports whether the clocks have diverged since start, which
+// means the system was suspended in between.
+func (s suspendClock) suspendedSince(start suspendClock) bool {
+	return (s.boot-start.boot)-(s.mono-start.mono) > suspendThreshold
+}
+
+// TimerOption configures optional behavior of a Timer.
+type TimerOption func(*timerOptions)
+
+type timerOptions struct {
+	precision uint
+	rounding  AlarmRounding
+	pastAlarm PastAlarmPolicy
+	// wake arms the wake alarm, which resumes a suspended system.
+	wake        bool
+	missedAlarm MissedAlarmPolicy
+	// progress enables update interrupts to report the countdown.
+	progress bool
+	// driftEvery is how often the alarm is adjusted for RTC drift.
+	driftEvery time.Duration
+	reference  ReferenceClock
+	inhibitor  SuspendInhibitor
+	// rtcOpts are passed to NewRTC when the device is opened.
+	rtcOpts []Option
+}
+
+// WithPrecision makes a Timer deliver its Alarm within a few milliseconds of
+// the requested time instead of the one second granularity of the hardware
+// alarm. The alarm is programmed shortly ahead of the requested time and the
+// final stretch is bridged with periodic interrupts at the given frequency.
+// The frequency must be a power of two supported by the device, and
+// frequencies above 64 Hz typically require root privileges.
+func WithPrecision(frequency uint) TimerOption {
+	return func(o *timerOptions) {
+		o.precision = frequency
+	}
+}
+
+// WithAlarmRounding sets how the Timer's alarm is rounded on devices whose
+// alarm resolution is coarser than the requested time, such as chips that
+// only support minute granularity. The default is AlarmRoundDown. It has no
+// effect on timers using WithPrecision, which bridge the rounding error with
+// periodic interrupts.
+func WithAlarmRounding(r AlarmRounding) TimerOption {
+	return func(o *timerOptions) {
+		o.rounding = r
+	}
+}
+
+// WithPastAlarmPolicy sets how the Timer handles an expiry time that is not in
+// the future. With PastAlarmFireNow the Timer fires immediately. The default
+// is PastAlarmAllow.
+func WithPastAlarmPolicy(p PastAlarmPolicy) TimerOption {
+	return func(o *timerOptions) {
+		o.pastAlarm = p
+	}
+}
+
+// WithRTCOptions sets the options the Timer opens the device with. Pass
+// WithLocalTime or WithAdjtime for an RTC that keeps local time, so that the
+// alarm is programmed in the RTC's time zone; otherwise the RTC is assumed to
+// keep UTC and the alarm is off by the local UTC offset.
+func WithRTCOptions(opts ...Option) TimerOption {
+	return func(o *timerOptions) {
+		o.rtcOpts = append(o.rtcOpts, opts...)
+	}
+}
+
+// ReferenceClock selects the clock that the expiry time of a Timer is given
+// against.
+type ReferenceClock int
+
+const (
+	// ReferenceRTC programs the expiry time into the alarm unchanged, so the
+	// Timer expires when the RTC reaches it.
+	ReferenceRTC ReferenceClock = iota
+	// ReferenceSystem translates the expiry time by the offset between the RTC
+	// and the system clock, so the Timer expires when the system clock
+	// reaches it. The offset is read with the one second resolution of the
+	// RTC.
+	ReferenceSystem
+)
+
+// WithReferenceClock sets the clock that the time passed to NewTimerAt and
+// similar constructors is measured against. The default is ReferenceRTC.
+func WithReferenceClock(r ReferenceClock) TimerOption {
+	return func(o *timerOptions) {
+		o.reference = r
+	}
+}
+
+// WithProgress makes a Timer also enable the update interrupt while waiting
+// for its alarm, and send a Progress on its Progress channel every second.
+func WithProgress() TimerOption {
+	return func(o *timerOptions) {
+		o.progress = true
+	}
+}
+
+// Progress reports the time remaining until a Timer expires.
+type Progress struct {
+	// Time is the system time of the update interrupt.
 	Time time.Time
+	// Remaining is the time left until expiry, rounded to the second.
+	Remaining time.Duration
 }
 
+// precisionLead is how far ahead of the requested time the hardware alarm is
+// programmed when bridging with periodic interrupts. It covers the unknown
+// phase between the RTC's second boundaries and the system clock.
+const precisionLead = 2 * time.Second
+
 type Timer struct {
-	done  chan struct{}
-	rtc   *RTC
-	fired atomic.Bool
-	C     <-chan Alarm
+	C <-chan Alarm
+	// Err receives the error that ended a run of the Timer without firing,
+	// such as a failure reading the device. The Alarm is then never delivered.
+	// It also receives the error of a suspend inhibitor lock that could not be
+	// held for the whole run, once the run ends. Errors are dropped while a
+	// previous one has not been received.
+	Err <-chan error
+	// Progress receives the countdown every second for timers created with
+	// WithProgress. If the client falls behind, updates are dropped until it
+	// catches up.
+	Progress <-chan Progress
+
+	// ctx stops the Timer when done.
+	ctx  context.Context
+	dev  string
+	o    timerOptions
+	ch   chan Alarm
+	errc chan error
+	prog chan Progress
+	// f is called instead of sending on ch for timers created by AfterFunc.
+	f func()
+
+	mu  sync.Mutex
+	run *timerRun
+}
+
+// timerRun is one arming of a Timer, ended by firing or by Stop.
+type timerRun struct {
+	// stop wakes the run's goroutine.
+	stop context.CancelFunc
+	// settled is set once the run has fired or been stopped.
+	settled atomic.Bool
+	// exited is closed once the run's goroutine has closed the device.
+	exited chan struct{}
+}
+
+// newTimer returns a Timer for the device that is not yet armed.
+func newTimer(ctx context.Context, dev string, opts []TimerOption, f func()) *Timer {
+	var o timerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// Give the channel a 1-element time buffer.
+	// If the client falls behind while reading, we drop ticks
+	// on the floor until the client catches up.
+	ch := make(chan Alarm, 1)
+	errc := make(chan error, 1)
+	prog := make(chan Progress, 1)
+	return &Timer{
+		C:        ch,
+		Err:      errc,
+		Progress: prog,
+		prog:     prog,
+		ctx:      ctx,
+		dev:      dev,
+		o:        o,
+		ch:       ch,
+		errc:     errc,
+		f:        f,
+	}
 }
 
 // NewTimerAt creates a new Timer that will send an Alarm on its channel after the given time.
-func NewTimerAt(dev string, t time.Time) (*Timer, error) {
-	c, err := NewRTC(dev)
+// t may be in any location; it is converted to the time zone the RTC keeps,
+// which is UTC unless set with WithRTCOptions. By default t is compared
+// against the RTC; see WithReferenceClock to compare it against the system
+// clock instead.
+func NewTimerAt(dev string, t time.Time, opts ...TimerOption) (*Timer, error) {
+	return NewTimerAtCtx(context.Background(), dev, t, opts...)
+}
+
+// NewTimerAtCtx is like NewTimerAt, but the Timer is stopped when the context
+// is done, disabling the alarm interrupt and closing the device, so that it
+// cannot leak on error paths that miss a call to Stop.
+func NewTimerAtCtx(ctx context.Context, dev string, t time.Time, opts ...TimerOption) (*Timer, error) {
+	timer := newTimer(ctx, dev, opts, nil)
+	if err := timer.armAt(t); err != nil {
+		return nil, err
+	}
+	return timer, nil
+}
+
+// NewTimerAtClock creates a new Timer like NewTimerAt that expires at the next
+// occurrence of the wall clock time clock, given as "15:04" or "15:04:05", in
+// loc. If loc is nil, the local time zone is used. A clock time skipped by a
+// daylight saving transition expires at the corresponding time after it.
+func NewTimerAtClock(dev string, clock string, loc *time.Location, opts ...TimerOption) (*Timer, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	at, err := nextClockTime(time.Now(), clock, loc)
 	if err != nil {
 		return nil, err
 	}
+	return NewTimerAt(dev, at, opts...)
+}
 
-	if err := c.SetAlarm(t); err != nil {
-		_ = c.Close()
+// nextClockTime returns the first time after now at which the wall clock in
+// loc reads clock.
+func nextClockTime(now time.Time, clock string, loc *time.Location) (t time.Time, err error) {
+	c, err := parseClock(clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	now = now.In(loc)
+	t = clockTimeOn(now.Year(), now.Month(), now.Day(), c, loc)
+	if !t.After(now) {
+		t = clockTimeOn(now.Year(), now.Month(), now.Day()+1, c, loc)
+	}
+	return t, nil
+}
+
+// clockTimeOn returns the time at which the wall clock in loc reads the clock
+// time of c on the given day. If a daylight saving transition skips it, the
+// time is moved forward by the gap.
+func clockTimeOn(year int, month time.Month, day int, c time.Time, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, c.Hour(), c.Minute(), c.Second(), 0, loc)
+	want := time.Date(year, month, day, c.Hour(), c.Minute(), c.Second(), 0, time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return t.Add(want.Sub(got))
+}
+
+// NewTimer creates a new Timer that will send an Alarm with the current time on its channel after at least duration d.
+// Durations beyond the 24 hour reach of the standard alarm are supported: the
+// wake alarm is used if the device has one, and intermediate alarms are
+// chained otherwise. If the expiry time is outside the range the device can
+// represent, a *RangeError is returned.
+func NewTimer(dev string, d time.Duration, opts ...TimerOption) (*Timer, error) {
+	return NewTimerCtx(context.Background(), dev, d, opts...)
+}
+
+// NewTimerCtx is like NewTimer, but the Timer is stopped when the context is
+// done, disabling the alarm interrupt and closing the device, so that it
+// cannot leak on error paths that miss a call to Stop.
+func NewTimerCtx(ctx context.Context, dev string, d time.Duration, opts ...TimerOption) (*Timer, error) {
+	timer := newTimer(ctx, dev, opts, nil)
+	if err := timer.armIn(d); err != nil {
 		return nil, err
 	}
+	return timer, nil
+}
 
-	if err := c.SetAlarmInterrupt(true); err != nil {
-		_ = c.Close()
+// After waits for the hardware alarm to fire after at least duration d and
+// then sends an Alarm on the returned channel, like time.After. The device is
+// closed once the alarm fires. Use NewTimer instead if the wait may need to be
+// cancelled.
+func After(dev string, d time.Duration, opts ...TimerOption) (<-chan Alarm, error) {
+	timer, err := NewTimer(dev, d, opts...)
+	if err != nil {
 		return nil, err
 	}
+	return timer.C, nil
+}
 
-	// Give the channel a 1-element time buffer.
-	// If the client falls behind while reading, we drop ticks
-	// on the floor until the client catches up.
-	ch := make(chan Alarm, 1)
-	timer := &Timer{
-		done: make(chan struct{}),
-		rtc:  c,
-		C:    ch,
+// NewTimerFromContext creates a new Timer like NewTimerAt that expires at the
+// deadline of ctx, and fires early when ctx is cancelled. The deadline timer
+// of a context runs on the monotonic clock, which stops while the system is
+// suspended; the Timer's alarm still fires on time after a resume. The Alarm
+// delivered on cancellation has a zero Event. The deadline is a system clock
+// time, so the Timer always uses ReferenceSystem. It is an error if ctx has
+// no deadline.
+func NewTimerFromContext(ctx context.Context, dev string, opts ...TimerOption) (*Timer, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil, errors.New("failed to create timer: context has no deadline")
+	}
+	opts = append(opts[:len(opts):len(opts)], WithReferenceClock(ReferenceSystem))
+	timer := newTimer(context.Background(), dev, opts, nil)
+	if err := timer.armAt(deadline); err != nil {
+		return nil, err
 	}
 
+	run := timer.run
 	go func() {
-		buf := make([]byte, 4)
-		_, err := syscall.Read(c.fd, buf)
-		if err != nil {
-			fmt.Printf("got error reading interrupt, returning\n")
-			return
-		}
-
 		select {
-		case <-timer.done:
-		// Don't send alarm if Stop() has been called
-		default:
-			timer.fired.Store(true)
+		case <-ctx.Done():
+			timer.expire(run)
+		case <-run.exited:
 		}
+	}()
+	return timer, nil
+}
 
-		// buf[0] = bit mask encoding the types of interrupt that occurred.
-		// buf[1:3] = number of interrupts since last read
-		//r := binary.LittleEndian.Uint32(buf)
-		//irqTypes := r & 0x000000FF
-		//fmt.Printf("r: 0x%X, types: 0x%X\n", r, irqTypes)
-		//cnt := r >> 8
+// expire fires run of the Timer immediately, unless it has already fired or
+// been stopped.
+func (t *Timer) expire(run *timerRun) {
+	if !run.settled.CompareAndSwap(false, true) {
+		return
+	}
+	run.stop()
+	t.deliver(Alarm{Time: time.Now()})
+}
 
-		ch <- Alarm{
-			Time: time.Now(),
-		}
-	}()
+// NewWakeTimerAt creates a new Timer like NewTimerAt, but armed with the wake
+// alarm (RTC_WKALM_SET), so that it also resumes the system if it is suspended
+// before the given time, as rtcwake does.
+// On devices without RTC_WKALM_SET the wake alarm only encodes a time of day,
+// and intermediate alarms cannot resume the system, so a *RangeError is
+// returned if t is more than 24 hours ahead.
+func NewWakeTimerAt(dev string, t time.Time, opts ...TimerOption) (*Timer, error) {
+	timer := newTimer(context.Background(), dev, opts, nil)
+	timer.o.wake = true
+	if err := timer.armAt(t); err != nil {
+		return nil, err
+	}
+	return timer, nil
+}
 
+// AfterFunc waits for the hardware alarm to fire after at least duration d and
+// then calls f in its own goroutine, like time.AfterFunc. The returned Timer's
+// Stop method cancels the call and its Reset method re-arms it. Its channel C
+// is not used.
+func AfterFunc(dev string, d time.Duration, f func(), opts ...TimerOption) (*Timer, error) {
+	timer := newTimer(context.Background(), dev, opts, f)
+	if err := timer.armIn(d); err != nil {
+		return nil, err
+	}
 	return timer, nil
 }
 
-// NewTimer creates a new Timer that will send an Alarm with the current time on its channel after at least duration d.
-func NewTimer(dev string, d time.Duration) (*Timer, error) {
-	c, err := NewRTC(dev)
+// armAt opens the device and arms the Timer to expire at t.
+func (t *Timer) armAt(at time.Time) (err error) {
+	c, err := NewRTC(t.dev, t.o.rtcOpts...)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	target, err := c.timerTarget(at, t.o.reference)
+	if err != nil {
+		_ = c.Close()
+		return err
 	}
+	alarm, err := c.armTimerAlarm(target, time.Until(at), t.o)
+	if err != nil {
+		_ = c.Close()
+		return err
+	}
+	return t.start(c, alarm, at)
+}
 
-	t, err := c.GetTime()
+// timerTarget returns the RTC time at which a Timer expiring at t against the
+// reference clock ref expires.
+func (c *RTC) timerTarget(t time.Time, ref ReferenceClock) (target time.Time, err error) {
+	if ref != ReferenceSystem {
+		return t, nil
+	}
+	sys := time.Now().Truncate(time.Second)
+	now, err := c.GetTime()
 	if err != nil {
-		return nil, err
+		return time.Time{}, err
+	}
+	return t.Add(now.Sub(sys)), nil
+}
+
+// armIn opens the device and arms the Timer to expire after duration d.
+func (t *Timer) armIn(d time.Duration) (err error) {
+	deadline := time.Now().Add(d)
+	c, err := NewRTC(t.dev, t.o.rtcOpts...)
+	if err != nil {
+		return err
 	}
 
-	if err := c.SetAlarm(t.Add(d)); err != nil {
+	now, err := c.GetTime()
+	if err != nil {
 		_ = c.Close()
-		return nil, err
+		return err
 	}
 
-	if err := c.SetAlarmInterrupt(true); err != nil {
+	alarm, err := c.armTimerAlarm(now.Add(d), d, t.o)
+	if err != nil {
 		_ = c.Close()
-		return nil, err
+		return err
 	}
+	return t.start(c, alarm, deadline)
+}
 
-	ch := make(chan Alarm, 1)
-	buf := make([]byte, 4)
-	timer := &Timer{
-		done: make(chan struct{}),
-		rtc:  c,
-		C:    ch,
+// start starts a run of the Timer whose goroutine waits for the armed alarm,
+// then counts down to deadline if precision is enabled, and delivers the
+// Alarm. The goroutine waits with poll alongside an eventfd, so that Stop
+// reliably wakes it, and it closes the device when it exits.
+func (t *Timer) start(c *RTC, alarm *timerAlarm, deadline time.Time) (err error) {
+	ctx, cancel := context.WithCancel(t.ctx)
+	w, release, err := newContextWaiter(ctx)
+	if err != nil {
+		cancel()
+		_ = c.Close()
+		return err
+	}
+	// Drift is compensated relative to the RTC target, which already accounts
+	// for the reference clock and precision.
+	var driftTotal time.Duration
+	if t.o.driftEvery > 0 && alarm != nil {
+		now, err := c.GetTime()
+		if err != nil {
+			release()
+			cancel()
+			_ = c.Close()
+			return err
+		}
+		driftTotal = alarm.t.Sub(now)
+	}
+	progress := t.o.progress && alarm != nil
+	if progress {
+		if err := c.SetUpdateInterrupt(true); err != nil {
+			release()
+			cancel()
+			_ = c.Close()
+			return err
+		}
+	}
+	var uninhibit func() error
+	if t.o.inhibitor != nil && !t.o.wake && alarm != nil {
+		if uninhibit, err = t.o.inhibitor.Inhibit("Waiting for real-time clock alarm"); err != nil {
+			if progress {
+				_ = c.SetUpdateInterrupt(false)
+			}
+			release()
+			cancel()
+			_ = c.Close()
+			return err
+		}
+	}
+	run := &timerRun{
+		stop:   cancel,
+		exited: make(chan struct{}),
+	}
+	t.mu.Lock()
+	t.run = run
+	t.mu.Unlock()
+
+	aw := alarmWait{
+		missed:     t.o.missedAlarm,
+		driftEvery: t.o.driftEvery,
+	}
+	if progress {
+		aw.tick = func() {
+			t.tick(deadline)
+		}
 	}
 
 	go func() {
-		_, err := syscall.Read(c.fd, buf)
-		if err != nil {
-			fmt.Printf("got error reading interrupt, returning: %v\n", err)
-			return
+		defer close(run.exited)
+		defer c.Close()
+		defer release()
+		if uninhibit != nil {
+			defer func() {
+				if err := uninhibit(); err != nil {
+					t.fail(err)
+				}
+			}()
+		}
+		if progress {
+			defer func() {
+				_ = c.SetUpdateInterrupt(false)
+			}()
 		}
 
-		select {
-		case <-timer.done:
+		started := readSuspendClock()
+		if aw.driftEvery > 0 {
+			aw.remaining = driftRemaining(started, driftTotal)
+		}
+		var e Event
+		var missed bool
+		if alarm != nil {
+			var err error
+			if e, missed, err = c.waitTimerAlarm(w, alarm, aw); err != nil {
+				if ctx.Err() == nil {
+					t.fail(fmt.Errorf("failed to wait for timer alarm: %w", err))
+					return
+				}
+				// Stopped, so the pending alarm must not fire, nor
+				// resume a suspended system.
+				if alarm.wake {
+					_ = c.CancelWakeAlarm()
+				} else {
+					_ = c.SetAlarmInterrupt(false)
+				}
+				return
+			}
+		}
+
+		if t.o.precision != 0 {
+			last, err := c.countdown(w, t.o.precision, deadline)
+			if err != nil {
+				if ctx.Err() == nil {
+					t.fail(fmt.Errorf("failed to count down to timer expiry: %w", err))
+				}
+				return
+			}
+			if last != (Event{}) {
+				e = last
+			}
+		}
+
+		a := Alarm{
+			Time:      time.Now(),
+			Event:     e,
+			Suspended: readSuspendClock().suspendedSince(started),
+			Missed:    missed,
+		}
+		a.RTCTime, _ = c.GetTime()
+
 		// Don't send alarm if Stop() has been called
-		default:
+		if !run.settled.CompareAndSwap(false, true) {
+			return
 		}
+		t.deliver(a)
+	}()
+
+	return nil
+}
+
+// deliver sends a on the Timer's channel, or calls the function of an
+// AfterFunc timer. The caller must have settled the run.
+func (t *Timer) deliver(a Alarm) {
+	if t.f != nil {
+		go t.f()
+		return
+	}
+	select {
+	case t.ch <- a:
+	default:
+	}
+}
+
+// tick sends the time remaining until deadline on the Progress channel,
+// unless a previous update is still pending.
+func (t *Timer) tick(deadline time.Time) {
+	now := time.Now()
+	remaining := deadline.Sub(now).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	select {
+	case t.prog <- Progress{Time: now, Remaining: remaining}:
+	default:
+	}
+}
+
+// fail delivers err on the Timer's error channel, unless a previous error is
+// still pending.
+func (t *Timer) fail(err error) {
+	select {
+	case t.errc <- err:
+	default:
+	}
+}
+
+// waitEvent waits with w for an interrupt and returns it. If w is woken
+// first, the returned error wraps ErrClosed.
+func (c *RTC) waitEvent(w *waiter) (e Event, err error) {
+	e, _, err = c.waitEventTimeout(w, -1)
+	return e, err
+}
+
+// waitEventTimeout is like waitEvent, but gives up after timeout, reporting
+// ok false. A negative timeout waits indefinitely.
+func (c *RTC) waitEventTimeout(w *waiter, timeout time.Duration) (e Event, ok bool, err error) {
+	readable, err := w.wait(c.fd(), timeout)
+	if err != nil {
+		return Event{}, false, err
+	}
+	if !readable {
+		if w.woken() {
+			return Event{}, false, fmt.Errorf("timer stopped: %w", ErrClosed)
+		}
+		return Event{}, false, nil
+	}
+	e, err = c.ReadEvent()
+	return e, err == nil, err
+}
+
+// armTimerAlarm programs the alarm for a Timer expiring at t, which is d from now.
+// With precision enabled, the alarm is moved ahead of t to leave room for the
+// periodic countdown, or skipped entirely if t is too close. The alarm is also
+// skipped if t is past and the policy is PastAlarmFireNow. Wake timers use the
+// wake alarm; otherwise times beyond the 24 hour reach of the standard alarm
+// are handled by armLongAlarm. It returns the armed alarm, or nil if the alarm
+// was skipped, and a *RangeError if t cannot be reached.
+func (c *RTC) armTimerAlarm(t time.Time, d time.Duration, o timerOptions) (alarm *timerAlarm, err error) {
+	if err := c.checkRange(t); err != nil {
+		return nil, err
+	}
+	c.SetAlarmRounding(o.rounding)
+	c.SetPastAlarmPolicy(o.pastAlarm)
+	if o.pastAlarm == PastAlarmFireNow {
+		now, err := c.GetTime()
+		if err != nil {
+			return nil, err
+		}
+		if alarmInPast(t, now) {
+			return nil, nil
+		}
+	}
+	if o.precision != 0 {
+		if d <= precisionLead {
+			return nil, nil
+		}
+		t = t.Add(-precisionLead)
+		// The countdown needs the alarm to fire early rather than late.
+		c.SetAlarmRounding(AlarmRoundDown)
+	}
 
-		ch <- Alarm{
-			Time: time.Now(),
+	if o.wake {
+		if err := c.checkWakeReach(t); err != nil {
+			return nil, err
+		}
+		if _, err := c.SetWakeAlarm(t); err != nil {
+			return nil, err
 		}
+		return &timerAlarm{t: t, wake: true}, nil
+	}
+	hop, err := c.armLongAlarm(t)
+	if err != nil {
+		return nil, err
+	}
+	return &timerAlarm{t: t, hop: hop}, nil
+}
+
+// countdown blocks until the system clock reaches deadline, counting periodic
+// interrupts at the given frequency with w, and returns the last interrupt
+// read. The alarm interrupt is disabled first so that only periodic
+// interrupts are consumed.
+func (c *RTC) countdown(w *waiter, frequency uint, deadline time.Time) (e Event, err error) {
+	if err := c.SetAlarmInterrupt(false); err != nil {
+		return e, err
+	}
+	if err := c.SetFrequency(frequency); err != nil {
+		return e, err
+	}
+	if err := c.SetPeriodicInterrupt(true); err != nil {
+		return e, err
+	}
+	defer func() {
+		_ = c.SetPeriodicInterrupt(false)
 	}()
 
-	return timer, nil
+	for time.Now().Before(deadline) {
+		if e, err = c.waitEvent(w); err != nil {
+			return e, err
+		}
+	}
+	return e, nil
 }
 
 // Stop prevents the Timer from firing.
 // It returns true if the call stops the timer, false if the timer has already
-// expired or been stopped.
+// expired or been stopped. Once Stop returns true, the run is guaranteed not
+// to deliver an Alarm or call the function of an AfterFunc timer; if it
+// returns false because the timer expired, the Alarm is delivered.
 // Stop does not close the channel, to prevent a read from the channel succeeding
 // incorrectly.
 //
@@ -146,7 +727,29 @@ func NewTimer(dev string, d time.Duration) (*Timer, error) {
 // This cannot be done concurrent to other receives from the Timer's
 // channel or other calls to the Timer's Stop method.
 func (t *Timer) Stop() bool {
-	close(t.done)
-	_ = t.rtc.Close()
-	return t.fired.Load()
+	t.mu.Lock()
+	run := t.run
+	t.mu.Unlock()
+	return run.halt()
+}
+
+// Reset stops the Timer and re-arms it to expire after duration d, reopening
+// the device. It returns true if the Timer had been active, false if it had
+// expired or been stopped. As with Stop, the channel should be drained before
+// calling Reset on a Timer that may have fired.
+func (t *Timer) Reset(d time.Duration) (active bool, err error) {
+	t.mu.Lock()
+	run := t.run
+	t.mu.Unlock()
+	active = run.halt()
+	// The previous run must release the alarm before it is armed again.
+	<-run.exited
+	return active, t.armIn(d)
+}
+
+// halt stops the run. It returns true if the run had not yet fired or been
+// stopped.
+func (r *timerRun) halt() bool {
+	r.stop()
+	return r.settled.CompareAndSwap(false, true)
 }
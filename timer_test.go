@@ -1,10 +1,18 @@
 package rtc
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sys/unix"
 )
 
 func TestNewTimerAt(t *testing.T) {
@@ -30,3 +38,429 @@ func TestNewTimer(t *testing.T) {
 		t.Error("alarm did not trigger in time")
 	}
 }
+
+func TestNewTimerAtWithPrecision(t *testing.T) {
+	target := time.Now().UTC().Add(3 * time.Second)
+	timer, err := NewTimerAt("/dev/rtc", target, WithPrecision(1024))
+	require.NoError(t, err)
+	defer timer.Stop()
+
+	select {
+	case alarm := <-timer.C:
+		require.WithinDuration(t, target, alarm.Time, 10*time.Millisecond)
+	case <-time.After(5 * time.Second):
+		t.Error("alarm did not trigger in time")
+	}
+}
+
+func TestTimerStopWakesReader(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+
+	timer := newTimer(context.Background(), os.DevNull, nil, nil)
+	require.NoError(t, timer.start(c, &timerAlarm{t: time.Now().Add(time.Hour)}, time.Now().Add(time.Hour)))
+	require.True(t, timer.Stop())
+	require.False(t, timer.Stop())
+
+	// The goroutine blocked waiting for the alarm exits and closes the device.
+	deadline := time.Now().Add(time.Second)
+	for c.fd() >= 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timer goroutine did not exit after Stop")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-timer.C:
+		t.Error("stopped timer fired")
+	default:
+	}
+}
+
+func TestAfterFunc(t *testing.T) {
+	called := make(chan struct{})
+	timer, err := AfterFunc("/dev/rtc", time.Second, func() {
+		close(called)
+	})
+	require.NoError(t, err)
+	defer timer.Stop()
+
+	select {
+	case <-called:
+	case <-time.After(3 * time.Second):
+		t.Error("function was not called in time")
+	}
+}
+
+func TestAfter(t *testing.T) {
+	ch, err := After("/dev/rtc", time.Second)
+	require.NoError(t, err)
+
+	select {
+	case <-ch:
+	case <-time.After(3 * time.Second):
+		t.Error("alarm did not trigger in time")
+	}
+}
+
+func TestSleep(t *testing.T) {
+	start := time.Now()
+	require.NoError(t, Sleep("/dev/rtc", 1500*time.Millisecond))
+	require.GreaterOrEqual(t, time.Since(start).Nanoseconds(), (1500 * time.Millisecond).Nanoseconds())
+}
+
+func TestTimerContextStops(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := newTimer(ctx, os.DevNull, nil, nil)
+	require.NoError(t, timer.start(c, &timerAlarm{t: time.Now().Add(time.Hour)}, time.Now().Add(time.Hour)))
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for c.fd() >= 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timer goroutine did not exit after the context was cancelled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNewWakeTimerAt(t *testing.T) {
+	timer, err := NewWakeTimerAt("/dev/rtc", time.Now().UTC().Add(2*time.Second))
+	require.NoError(t, err)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-time.After(4 * time.Second):
+		t.Error("alarm did not trigger in time")
+	}
+}
+
+func TestSuspendedSince(t *testing.T) {
+	start := suspendClock{boot: 10 * time.Second, mono: 5 * time.Second}
+	assert.False(t, suspendClock{boot: 20 * time.Second, mono: 15 * time.Second}.suspendedSince(start))
+	assert.True(t, suspendClock{boot: 80 * time.Second, mono: 15 * time.Second}.suspendedSince(start))
+	assert.False(t, readSuspendClock().suspendedSince(readSuspendClock()))
+}
+
+func TestArmTimerAlarmRange(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+	defer c.Close()
+
+	far := time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	_, err = c.armTimerAlarm(far, time.Until(far), timerOptions{})
+	var rangeErr *RangeError
+	require.True(t, errors.As(err, &rangeErr))
+	assert.True(t, far.Equal(rangeErr.Time))
+}
+
+func TestTimerReportsError(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	c := &RTC{f: r}
+
+	timer := newTimer(context.Background(), os.DevNull, nil, nil)
+	require.NoError(t, timer.start(c, &timerAlarm{t: time.Now().Add(time.Hour)}, time.Now().Add(time.Hour)))
+	defer timer.Stop()
+
+	// Hanging up the device makes waiting for the alarm fail.
+	require.NoError(t, w.Close())
+	select {
+	case err := <-timer.Err:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timer did not report the error")
+	}
+	select {
+	case <-timer.C:
+		t.Error("failed timer fired")
+	default:
+	}
+}
+
+// startPipeTimer starts a run of timer on an RTC backed by a pipe, and returns
+// a function that delivers an alarm interrupt to it.
+func startPipeTimer(t *testing.T, timer *Timer) (fire func()) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Close() })
+
+	c := &RTC{f: r}
+	require.NoError(t, timer.start(c, &timerAlarm{t: time.Now().Add(time.Hour)}, time.Now().Add(time.Hour)))
+	return func() {
+		buf := make([]byte, 4)
+		binary.LittleEndian.PutUint32(buf, 1<<8|unix.RTC_AF)
+		_, err := w.Write(buf)
+		require.NoError(t, err)
+	}
+}
+
+func TestTimerStopAfterFire(t *testing.T) {
+	timer := newTimer(context.Background(), os.DevNull, nil, nil)
+	fire := startPipeTimer(t, timer)
+	fire()
+
+	select {
+	case alarm := <-timer.C:
+		assert.True(t, alarm.Event.Alarm)
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+	assert.False(t, timer.Stop())
+}
+
+func TestTimerStopExcludesFire(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		timer := newTimer(context.Background(), os.DevNull, nil, nil)
+		fire := startPipeTimer(t, timer)
+		fire()
+		stopped := timer.Stop()
+		<-timer.run.exited
+
+		// Like time.Timer, a value is delivered if and only if Stop reports
+		// that the timer had already expired.
+		select {
+		case <-timer.C:
+			assert.False(t, stopped, "stopped timer fired")
+		default:
+			assert.True(t, stopped, "expired timer did not deliver")
+		}
+	}
+}
+
+func TestAfterFuncStopExcludesCall(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		called := make(chan struct{}, 1)
+		timer := newTimer(context.Background(), os.DevNull, nil, func() { called <- struct{}{} })
+		fire := startPipeTimer(t, timer)
+		fire()
+		stopped := timer.Stop()
+		<-timer.run.exited
+
+		if stopped {
+			select {
+			case <-called:
+				t.Fatal("stopped timer called its function")
+			case <-time.After(time.Millisecond):
+			}
+			continue
+		}
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("expired timer did not call its function")
+		}
+	}
+}
+
+func TestNextClockTime(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2030, time.March, 4, 10, 0, 0, 0, time.UTC) // 12:00 in loc
+
+	at, err := nextClockTime(now, "13:30", loc)
+	require.NoError(t, err)
+	assert.True(t, time.Date(2030, time.March, 4, 11, 30, 0, 0, time.UTC).Equal(at))
+
+	// Clock times already passed today expire tomorrow
+	at, err = nextClockTime(now, "12:00:00", loc)
+	require.NoError(t, err)
+	assert.True(t, time.Date(2030, time.March, 5, 10, 0, 0, 0, time.UTC).Equal(at))
+
+	_, err = nextClockTime(now, "25:00", loc)
+	assert.Error(t, err)
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	// 02:30 does not exist on the day daylight saving time starts
+	now = time.Date(2030, time.March, 10, 0, 0, 0, 0, ny)
+	at, err = nextClockTime(now, "02:30", ny)
+	require.NoError(t, err)
+	assert.True(t, time.Date(2030, time.March, 10, 7, 30, 0, 0, time.UTC).Equal(at))
+}
+
+func TestWaitEventTimeout(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+	defer c.Close()
+	wt, err := newWaiter()
+	require.NoError(t, err)
+	defer wt.close()
+
+	_, ok, err := c.waitEventTimeout(wt, time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, 1<<8|unix.RTC_AF)
+	_, err = w.Write(buf)
+	require.NoError(t, err)
+	e, ok, err := c.waitEventTimeout(wt, time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, e.Alarm)
+
+	wt.wake()
+	_, ok, err = c.waitEventTimeout(wt, time.Second)
+	assert.False(t, ok)
+	assert.True(t, errors.Is(err, ErrClosed))
+}
+
+func TestWaitTimerAlarmTicks(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	c := &RTC{f: r}
+	defer c.Close()
+	wt, err := newWaiter()
+	require.NoError(t, err)
+	defer wt.close()
+
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf[0:], 1<<8|unix.RTC_UF)
+	binary.LittleEndian.PutUint32(buf[4:], 1<<8|unix.RTC_AF)
+	_, err = w.Write(buf)
+	require.NoError(t, err)
+
+	ticks := 0
+	e, missed, err := c.waitTimerAlarm(wt, &timerAlarm{t: time.Now()}, alarmWait{
+		missed: MissedAlarmWait,
+		tick:   func() { ticks++ },
+	})
+	require.NoError(t, err)
+	assert.True(t, e.Alarm)
+	assert.False(t, missed)
+	assert.Equal(t, 1, ticks)
+}
+
+func TestTimerProgress(t *testing.T) {
+	timer, err := NewTimer("/dev/rtc", 3*time.Second, WithProgress())
+	require.NoError(t, err)
+	defer timer.Stop()
+
+	select {
+	case p := <-timer.Progress:
+		assert.True(t, p.Remaining > 0 && p.Remaining <= 3*time.Second)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timer did not report progress in time")
+	}
+	select {
+	case <-timer.C:
+	case <-time.After(5 * time.Second):
+		t.Error("timer did not fire in time")
+	}
+}
+
+func TestTimerExpire(t *testing.T) {
+	timer := newTimer(context.Background(), os.DevNull, nil, nil)
+	startPipeTimer(t, timer)
+	timer.expire(timer.run)
+	<-timer.run.exited
+
+	select {
+	case alarm := <-timer.C:
+		assert.False(t, alarm.Event.Alarm)
+	default:
+		t.Error("expired timer did not deliver")
+	}
+	assert.False(t, timer.Stop())
+}
+
+func TestNewTimerFromContext(t *testing.T) {
+	_, err := NewTimerFromContext(context.Background(), "/dev/rtc")
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	timer, err := NewTimerFromContext(ctx, "/dev/rtc")
+	require.NoError(t, err)
+	defer timer.Stop()
+	// The deadline is a system clock time
+	assert.Equal(t, ReferenceSystem, timer.o.reference)
+
+	select {
+	case <-timer.C:
+	case <-time.After(4 * time.Second):
+		t.Error("timer did not fire in time")
+	}
+}
+
+func TestTimerTarget(t *testing.T) {
+	at := time.Now().Add(time.Hour)
+	c := &RTC{}
+	target, err := c.timerTarget(at, ReferenceRTC)
+	require.NoError(t, err)
+	assert.True(t, at.Equal(target))
+
+	c, err = NewRTC("/dev/rtc", WithReadOnly())
+	require.NoError(t, err)
+	defer c.Close()
+	now, err := c.GetTime()
+	require.NoError(t, err)
+	target, err = c.timerTarget(at, ReferenceSystem)
+	require.NoError(t, err)
+	// The target is as far ahead of the RTC as at is of the system clock
+	assert.InDelta(t, float64(time.Until(at)), float64(target.Sub(now)), float64(2*time.Second))
+}
+
+// fakeInhibitor counts the suspend inhibitor locks it holds.
+type fakeInhibitor struct {
+	held       int32
+	releaseErr error
+}
+
+func (f *fakeInhibitor) Inhibit(why string) (release func() error, err error) {
+	atomic.AddInt32(&f.held, 1)
+	return func() error {
+		atomic.AddInt32(&f.held, -1)
+		return f.releaseErr
+	}, nil
+}
+
+func TestTimerSuspendInhibitor(t *testing.T) {
+	inhibitor := &fakeInhibitor{}
+	timer := newTimer(context.Background(), os.DevNull, []TimerOption{WithSuspendInhibitor(inhibitor)}, nil)
+	startPipeTimer(t, timer)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&inhibitor.held), "pending timer did not inhibit suspend")
+
+	require.True(t, timer.Stop())
+	<-timer.run.exited
+	assert.Equal(t, int32(0), atomic.LoadInt32(&inhibitor.held), "stopped timer did not release the lock")
+
+	// Wake timers can resume the system themselves
+	timer = newTimer(context.Background(), os.DevNull, []TimerOption{WithSuspendInhibitor(inhibitor)}, nil)
+	timer.o.wake = true
+	startPipeTimer(t, timer)
+	defer timer.Stop()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&inhibitor.held))
+}
+
+func TestTimerSuspendInhibitorLost(t *testing.T) {
+	lost := errors.New("lock lost")
+	inhibitor := &fakeInhibitor{releaseErr: lost}
+	timer := newTimer(context.Background(), os.DevNull, []TimerOption{WithSuspendInhibitor(inhibitor)}, nil)
+	startPipeTimer(t, timer)
+	require.True(t, timer.Stop())
+	<-timer.run.exited
+
+	select {
+	case err := <-timer.Err:
+		assert.True(t, errors.Is(err, lost))
+	default:
+		t.Error("lost inhibitor lock not reported")
+	}
+}
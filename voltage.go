@@ -0,0 +1,79 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// VoltageLow holds the battery and data validity flags reported by
+// RTC_VL_READ.
+type VoltageLow uint32
+
+// Voltage low flags. They are not defined by golang.org/x/sys/unix.
+const (
+	// VoltageLowDataInvalid is set when the time held by the RTC cannot be
+	// trusted, typically because the oscillator stopped.
+	VoltageLowDataInvalid VoltageLow = 1 << iota
+	// VoltageLowBackupLow is set when the backup battery voltage is low.
+	VoltageLowBackupLow
+	// VoltageLowBackupEmpty is set when the backup battery is empty.
+	VoltageLowBackupEmpty
+	// VoltageLowAccuracyLow is set when the voltage is too low for the RTC
+	// to keep accurate time.
+	VoltageLowAccuracyLow
+	// VoltageLowBackupSwitch is set when the RTC has switched to the backup
+	// power supply.
+	VoltageLowBackupSwitch
+)
+
+var voltageLowNames = []string{
+	"data invalid",
+	"backup low",
+	"backup empty",
+	"accuracy low",
+	"backup switch",
+}
+
+func (v VoltageLow) String() string {
+	if v == 0 {
+		return "ok"
+	}
+	var names []string
+	for i, name := range voltageLowNames {
+		if v&(1<<uint(i)) != 0 {
+			names = append(names, name)
+			v &^= 1 << uint(i)
+		}
+	}
+	if v != 0 {
+		names = append(names, fmt.Sprintf("0x%x", uint32(v)))
+	}
+	return strings.Join(names, "|")
+}
+
+// GetVoltageLow returns the real-time clock's voltage low flags, read with
+// RTC_VL_READ. Embedded devices should check these before trusting the RTC
+// time, as a dead coin cell leaves the time invalid after a power loss.
+func (c *RTC) GetVoltageLow() (flags VoltageLow, err error) {
+	v := new(uint32)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_VL_READ, uintptr(unsafe.Pointer(v))); errno != 0 {
+		return 0, fmt.Errorf("failed to read real-time clock voltage low flags: %w", wrapErrno(errno))
+	}
+	return VoltageLow(*v), nil
+}
+
+// ClearVoltageLow clears the real-time clock's voltage low flags with
+// RTC_VL_CLR, for example after the battery was replaced.
+func (c *RTC) ClearVoltageLow() (err error) {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(c.fd()), unix.RTC_VL_CLR, 0); errno != 0 {
+		return fmt.Errorf("failed to clear real-time clock voltage low flags: %w", wrapErrno(errno))
+	}
+	return nil
+}
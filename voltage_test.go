@@ -0,0 +1,14 @@
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoltageLowString(t *testing.T) {
+	assert.Equal(t, "ok", VoltageLow(0).String())
+	assert.Equal(t, "data invalid", VoltageLowDataInvalid.String())
+	assert.Equal(t, "backup low|backup switch", (VoltageLowBackupLow | VoltageLowBackupSwitch).String())
+	assert.Equal(t, "accuracy low|0x40", (VoltageLowAccuracyLow | 0x40).String())
+}
@@ -0,0 +1,92 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"context"
+	"time"
+)
+
+// maxWaitRecheck bounds how long WaitUntil waits before comparing the RTC time
+// against the target again.
+const maxWaitRecheck = time.Minute
+
+// WaitUntil blocks until the specified real-time clock device reaches time t,
+// or the context is done.
+// Unlike time.Sleep, which is measured with a clock that stops while the
+// system is suspended, the wait is armed as an RTC wake alarm. The system
+// resumes at t if it is suspended, and the deadline is checked against the RTC
+// time, so the wait completes on schedule across suspend and resume.
+// On devices with coarse alarm resolution the alarm is rounded up, so the wait
+// never completes early.
+// The wake alarm is cancelled when WaitUntil returns.
+func WaitUntil(ctx context.Context, dev string, t time.Time) (err error) {
+	c, err := NewRTC(dev)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// Never return early, even on devices with coarse alarms.
+	c.SetAlarmRounding(AlarmRoundUp)
+	t, _ = roundAlarm(t, time.Second, AlarmRoundUp)
+	target := timeRtc{Time: t}.truncated()
+	now, err := c.GetTime()
+	if err != nil {
+		return err
+	}
+	if !target.After(now) {
+		return nil
+	}
+
+	if _, err := c.SetWakeAlarm(t); err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.CancelWakeAlarm()
+	}()
+
+	w, release, err := newContextWaiter(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	for {
+		// Wake up periodically because the poll timeout does not advance
+		// while suspended, and the alarm may be lost if the system resumed
+		// for another reason after t.
+		timeout := target.Sub(now)
+		if timeout > maxWaitRecheck {
+			timeout = maxWaitRecheck
+		}
+		if timeout < time.Second {
+			timeout = time.Second
+		}
+
+		readable, err := w.wait(c.fd(), timeout)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if readable {
+			e, err := c.ReadEvent()
+			if err != nil {
+				return err
+			}
+			if e.Alarm {
+				return nil
+			}
+		}
+
+		if now, err = c.GetTime(); err != nil {
+			return err
+		}
+		if !target.After(now) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package rtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitUntil(t *testing.T) {
+	now, err := GetTime("/dev/rtc")
+	require.NoError(t, err)
+
+	require.NoError(t, WaitUntil(context.Background(), "/dev/rtc", now.Add(2*time.Second)))
+
+	after, err := GetTime("/dev/rtc")
+	require.NoError(t, err)
+	assert.False(t, after.Before(now.Add(2*time.Second)))
+}
+
+func TestWaitUntilCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := WaitUntil(ctx, "/dev/rtc", time.Now().UTC().Add(time.Hour))
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
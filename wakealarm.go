@@ -0,0 +1,77 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// SysfsWakeAlarm returns the wake alarm time from the wakealarm sysfs
+// attribute, or the zero time if no wake alarm is set.
+func (c *RTC) SysfsWakeAlarm() (t time.Time, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return time.Time{}, err
+	}
+	rt, ok := readSysfsTime(filepath.Join(dir, "wakealarm"))
+	if !ok {
+		return time.Time{}, nil
+	}
+	return c.fromRTC(*timeRtc{Time: rt}.rtcTime()), nil
+}
+
+// SetSysfsWakeAlarm sets and enables the wake alarm by writing the wakealarm
+// sysfs attribute, which requires root privileges. Some drivers accept it but
+// reject RTC_WKALM_SET, and SetWakeAlarm falls back to it automatically.
+// It returns the effective alarm time.
+func (c *RTC) SetSysfsWakeAlarm(t time.Time) (effective time.Time, err error) {
+	if err := c.checkRange(t); err != nil {
+		return time.Time{}, err
+	}
+	t, err = c.effectiveAlarm(t)
+	if err != nil {
+		return time.Time{}, err
+	}
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return time.Time{}, err
+	}
+	// The attribute holds seconds since the epoch of the RTC's own time.
+	sec := rtcTime{*c.toRTC(t)}.time().Unix()
+	if err := writeSysfsWakeAlarm(dir, sec); err != nil {
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+// CancelSysfsWakeAlarm cancels the wake alarm by writing the wakealarm sysfs
+// attribute, which requires root privileges.
+func (c *RTC) CancelSysfsWakeAlarm() (err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return err
+	}
+	return writeSysfsWakeAlarm(dir, 0)
+}
+
+// writeSysfsWakeAlarm writes sec to the wakealarm attribute in the sysfs
+// directory dir, cancelling the wake alarm if sec is 0. The kernel refuses to
+// replace an armed alarm, so any alarm is cancelled first.
+func writeSysfsWakeAlarm(dir string, sec int64) (err error) {
+	path := filepath.Join(dir, "wakealarm")
+	if err := os.WriteFile(path, []byte("0"), 0644); err != nil {
+		return fmt.Errorf("failed to cancel real-time clock wake alarm: %w", wrapErrno(err))
+	}
+	if sec == 0 {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(strconv.FormatInt(sec, 10)), 0644); err != nil {
+		return fmt.Errorf("failed to set real-time clock wake alarm: %w", wrapErrno(err))
+	}
+	return nil
+}
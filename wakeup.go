@@ -0,0 +1,70 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Wakeup reports whether the real-time clock can wake the system from sleep,
+// and whether that is currently enabled, read from the power/wakeup sysfs
+// attribute of its parent device. Programs scheduling wakes from suspend
+// should check it before relying on SetWakeAlarm.
+func (c *RTC) Wakeup() (capable bool, enabled bool, err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return false, false, err
+	}
+	capable, enabled = readWakeup(dir)
+	return capable, enabled, nil
+}
+
+// SetWakeup enables or disables the real-time clock's ability to wake the
+// system from sleep by writing the power/wakeup sysfs attribute of its parent
+// device, which requires root privileges. For devices that cannot wake the
+// system the returned error wraps ErrNotSupported.
+func (c *RTC) SetWakeup(enable bool) (err error) {
+	dir, err := c.sysfsDir()
+	if err != nil {
+		return err
+	}
+	return writeWakeup(dir, enable)
+}
+
+// wakeupPath returns the path of the power/wakeup attribute of the parent
+// device of the class device in the sysfs directory dir.
+func wakeupPath(dir string) string {
+	return filepath.Join(dir, "device", "power", "wakeup")
+}
+
+// readWakeup reads the wakeup capability and state of the class device in the
+// sysfs directory dir. The attribute only exists for wakeup capable devices.
+func readWakeup(dir string) (capable bool, enabled bool) {
+	b, err := os.ReadFile(wakeupPath(dir))
+	if err != nil {
+		return false, false
+	}
+	state := strings.TrimSpace(string(b))
+	return state != "", state == "enabled"
+}
+
+// writeWakeup sets the wakeup state of the class device in the sysfs
+// directory dir.
+func writeWakeup(dir string, enable bool) (err error) {
+	path := wakeupPath(dir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("failed to access real-time clock wakeup: %w", ErrNotSupported)
+	}
+	state := "disabled"
+	if enable {
+		state = "enabled"
+	}
+	if err := os.WriteFile(path, []byte(state), 0644); err != nil {
+		return fmt.Errorf("failed to set real-time clock wakeup: %w", wrapErrno(err))
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+//go:build !windows
+// +build !windows
+
+package rtc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ClockAction is the kind of change reported by a ClockEvent.
+type ClockAction int
+
+const (
+	// ClockAdded is reported when a real-time clock appears.
+	ClockAdded ClockAction = iota
+	// ClockRemoved is reported when a real-time clock disappears.
+	ClockRemoved
+)
+
+func (a ClockAction) String() string {
+	switch a {
+	case ClockAdded:
+		return "added"
+	case ClockRemoved:
+		return "removed"
+	default:
+		return fmt.Sprintf("ClockAction(%d)", int(a))
+	}
+}
+
+// ClockEvent reports a real-time clock appearing or disappearing.
+type ClockEvent struct {
+	Action ClockAction
+	// Name is the class device name, such as "rtc1".
+	Name string
+	// DevNode is the device node, such as "/dev/rtc1".
+	DevNode string
+}
+
+// WatchClocks listens to kernel uevents and reports real-time clocks, such as
+// USB and I2C RTCs, appearing and disappearing at runtime. The returned
+// channel is closed when ctx is done or listening fails.
+func WatchClocks(ctx context.Context) (<-chan ClockEvent, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uevent socket: %w", wrapErrno(err))
+	}
+	// Group 1 receives the uevents broadcast by the kernel.
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: 1}); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to bind uevent socket: %w", wrapErrno(err))
+	}
+	w, release, err := newContextWaiter(ctx)
+	if err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+
+	ch := make(chan ClockEvent)
+	go func() {
+		defer close(ch)
+		defer unix.Close(fd)
+		defer release()
+		buf := make([]byte, 8192)
+		for {
+			readable, err := w.wait(fd, -1)
+			if err != nil || !readable {
+				return
+			}
+			n, _, err := unix.Recvfrom(fd, buf, 0)
+			if err != nil {
+				return
+			}
+			e, ok := parseUevent(buf[:n])
+			if !ok {
+				continue
+			}
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// parseUevent returns the ClockEvent described by a kernel uevent message, a
+// header followed by NUL separated KEY=value pairs. It reports false for
+// uevents other than rtc class devices being added or removed.
+func parseUevent(msg []byte) (e ClockEvent, ok bool) {
+	env := make(map[string]string)
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		if kv := strings.SplitN(string(field), "=", 2); len(kv) == 2 {
+			env[kv[0]] = kv[1]
+		}
+	}
+	if env["SUBSYSTEM"] != "rtc" {
+		return ClockEvent{}, false
+	}
+	switch env["ACTION"] {
+	case "add":
+		e.Action = ClockAdded
+	case "remove":
+		e.Action = ClockRemoved
+	default:
+		return ClockEvent{}, false
+	}
+	e.Name = env["DEVNAME"]
+	if e.Name == "" {
+		e.Name = filepath.Base(env["DEVPATH"])
+	}
+	e.DevNode = filepath.Join(devDir(), e.Name)
+	return e, true
+}
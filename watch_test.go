@@ -0,0 +1,29 @@
+package rtc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func uevent(fields ...string) []byte {
+	return []byte(strings.Join(fields, "\x00") + "\x00")
+}
+
+func TestParseUevent(t *testing.T) {
+	e, ok := parseUevent(uevent("add@/devices/pci0000:00/usb1/1-1/rtc/rtc1", "ACTION=add",
+		"DEVPATH=/devices/pci0000:00/usb1/1-1/rtc/rtc1", "SUBSYSTEM=rtc", "MAJOR=252", "MINOR=1", "DEVNAME=rtc1", "SEQNUM=1234"))
+	assert.True(t, ok)
+	assert.Equal(t, ClockEvent{Action: ClockAdded, Name: "rtc1", DevNode: "/dev/rtc1"}, e)
+
+	e, ok = parseUevent(uevent("remove@/devices/i2c-1/1-0068/rtc/rtc2", "ACTION=remove",
+		"DEVPATH=/devices/i2c-1/1-0068/rtc/rtc2", "SUBSYSTEM=rtc"))
+	assert.True(t, ok)
+	assert.Equal(t, ClockEvent{Action: ClockRemoved, Name: "rtc2", DevNode: "/dev/rtc2"}, e)
+
+	_, ok = parseUevent(uevent("change@/devices/i2c-1/1-0068/rtc/rtc2", "ACTION=change", "SUBSYSTEM=rtc", "DEVNAME=rtc2"))
+	assert.False(t, ok)
+	_, ok = parseUevent(uevent("add@/devices/virtual/block/loop0", "ACTION=add", "SUBSYSTEM=block", "DEVNAME=loop0"))
+	assert.False(t, ok)
+}